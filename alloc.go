@@ -0,0 +1,90 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// AllocValue contains a value initialized with the make or new builtin, e.g. a labeled map without literal entries:
+//
+//	// someLabel
+//	var cache = make(map[string]int, 8) // Builtin "make", AllocType "map[string]int", Sizes [8]
+type AllocValue struct {
+	DeclInfo
+
+	// Builtin is "make" or "new"
+	Builtin string
+
+	// AllocType is the allocated type written as in the source
+	AllocType string
+
+	// Sizes are the size arguments of make: the length and the capacity of a slice, the size hint of a map
+	// or the buffer size of a channel; -1 for arguments that aren't integer literals
+	Sizes []int64
+
+	// Source is the source of the call, e.g. "make(map[string]int, 8)"
+	Source string
+}
+
+// Result returns the value as Result, its value is the source of the call
+func (v AllocValue) Result() Result {
+	return newResult(KindAlloc, v.DeclInfo, v.Source)
+}
+
+// GetAllocValues returns a list of values initialized with the make or new builtin by godoc label
+func GetAllocValues(g *GoParser, docLabels ...string) []AllocValue {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return walkDecls(g, docMap, newAllocValue)
+}
+
+// newAllocValue returns the allocated value or nil if the value isn't a call of make or new
+func newAllocValue(info DeclInfo, val ast.Expr) *AllocValue {
+	builtin, call, ok := allocCall(val)
+	if !ok {
+		return nil
+	}
+
+	v := &AllocValue{
+		DeclInfo:  info,
+		Builtin:   builtin,
+		AllocType: types.ExprString(call.Args[0]),
+		Source:    types.ExprString(call),
+	}
+
+	for _, arg := range call.Args[1:] {
+		size := int64(-1)
+		if lit, ok := basicLit(arg); ok && lit.Kind == token.INT {
+			if s, err := strconv.ParseInt(lit.Value, 0, 64); err == nil {
+				size = s
+			}
+		}
+		v.Sizes = append(v.Sizes, size)
+	}
+
+	return v
+}
+
+// allocCall returns the call of the make or new builtin (not shadowed by a declaration of the file)
+func allocCall(val ast.Expr) (builtin string, call *ast.CallExpr, ok bool) {
+	call, ok = val.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", nil, false
+	}
+
+	id, ok := call.Fun.(*ast.Ident)
+	if !ok || id.Obj != nil || (id.Name != "make" && id.Name != "new") {
+		return "", nil, false
+	}
+
+	return id.Name, call, true
+}
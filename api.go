@@ -0,0 +1,295 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// API symbol kinds
+const (
+	APIFunc            = "func"
+	APIMethod          = "method"
+	APIType            = "type"
+	APIField           = "field"
+	APIInterfaceMethod = "interface method" // embedded interfaces are listed as interface methods too
+	APIConst           = "const"
+	APIVar             = "var"
+)
+
+// APISymbol contains an exported identifier of a package; members are named as "Type.Member"
+type APISymbol struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// Signature is the type of the symbol without names of params, e.g. "func(string, int) error";
+	// it's the kind for struct and interface types (see TypeDoc) and empty for untyped constants and variables
+	// without a declared type in untyped mode
+	Signature string `json:"signature"`
+}
+
+// APISnapshot is the exported API surface of a package, it can be stored as JSON and compared with CompareAPI
+type APISnapshot struct {
+	Package string      `json:"package"`
+	Symbols []APISymbol `json:"symbols"`
+}
+
+// APISurface returns exported functions, types, methods, struct fields, constants and variables
+// of parsed non-test files sorted by name; in typed mode types of constants and variables without a declared type
+// are taken from type information
+func APISurface(g *GoParser) *APISnapshot {
+	s := &APISnapshot{Symbols: make([]APISymbol, 0)}
+
+	add := func(kind, name, sig string) {
+		s.Symbols = append(s.Symbols, APISymbol{Kind: kind, Name: name, Signature: sig})
+	}
+
+	for _, f := range g.files {
+		if strings.HasSuffix(f.path, "_test.go") {
+			continue
+		}
+
+		if s.Package == "" {
+			s.Package = f.ast.Name.Name
+		}
+
+		for _, d := range f.ast.Decls {
+			switch decl := d.(type) {
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+
+				rec := recvTypeName(decl)
+				switch {
+				case rec == "":
+					add(APIFunc, decl.Name.Name, typeParams(decl.Type.TypeParams)+funcTypeString(decl.Type))
+				case ast.IsExported(rec):
+					sig := funcTypeString(decl.Type)
+					if _, ok := decl.Recv.List[0].Type.(*ast.StarExpr); ok {
+						sig = "(*) " + sig // pointer receivers change method sets
+					}
+					add(APIMethod, funcName(decl), sig)
+				}
+			case *ast.GenDecl:
+				switch decl.Tok {
+				case token.TYPE:
+					for _, spec := range decl.Specs {
+						ts := spec.(*ast.TypeSpec)
+						if ts.Name.IsExported() {
+							apiType(ts, add)
+						}
+					}
+				case token.CONST, token.VAR:
+					kind := APIVar
+					if decl.Tok == token.CONST {
+						kind = APIConst
+					}
+
+					var typ ast.Expr
+					for _, spec := range decl.Specs {
+						vs := spec.(*ast.ValueSpec)
+						if decl.Tok == token.VAR || vs.Type != nil || len(vs.Values) > 0 {
+							typ = vs.Type // constants without values inherit the type of the previous spec
+						}
+
+						for _, n := range vs.Names {
+							if !n.IsExported() {
+								continue
+							}
+
+							sig := ""
+							if typ != nil {
+								sig = types.ExprString(typ)
+							}
+							if t := g.objectType(n); t != "" {
+								sig = t
+							}
+
+							add(kind, n.Name, sig)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(s.Symbols, func(i, j int) bool {
+		a, b := s.Symbols[i], s.Symbols[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Kind < b.Kind
+	})
+
+	return s
+}
+
+// apiType adds symbols of the type declaration and its exported members
+func apiType(ts *ast.TypeSpec, add func(kind, name, sig string)) {
+	name := ts.Name.Name
+
+	sig := typeKind(ts.Type)
+	if sig != "struct" && sig != "interface" {
+		sig = types.ExprString(ts.Type)
+	}
+	if ts.Assign.IsValid() {
+		sig = "= " + sig
+	}
+	add(APIType, name, typeParams(ts.TypeParams)+sig)
+
+	switch t := ts.Type.(type) {
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			typ := types.ExprString(field.Type)
+
+			if len(field.Names) == 0 {
+				if embedded := typeName(field.Type); ast.IsExported(embedded) {
+					add(APIField, name+"."+embedded, "embedded "+typ)
+				}
+				continue
+			}
+
+			for _, n := range field.Names {
+				if n.IsExported() {
+					add(APIField, name+"."+n.Name, typ)
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		for _, m := range t.Methods.List {
+			ft, ok := m.Type.(*ast.FuncType)
+			if !ok {
+				add(APIInterfaceMethod, name+"."+types.ExprString(m.Type), "embedded")
+				continue
+			}
+
+			for _, n := range m.Names {
+				add(APIInterfaceMethod, name+"."+n.Name, funcTypeString(ft))
+			}
+		}
+	}
+}
+
+// funcTypeString returns the function type without names of params and results, e.g. "func(string, int) error"
+func funcTypeString(ft *ast.FuncType) string {
+	sig := "func(" + fieldTypes(ft.Params) + ")"
+
+	if ft.Results == nil || len(ft.Results.List) == 0 {
+		return sig
+	}
+
+	results := fieldTypes(ft.Results)
+	if len(ft.Results.List) == 1 && len(ft.Results.List[0].Names) <= 1 {
+		return sig + " " + results
+	}
+
+	return sig + " (" + results + ")"
+}
+
+// typeParams returns constraints of type params, e.g. "[any, comparable] ", empty without type params
+func typeParams(fields *ast.FieldList) string {
+	if fields == nil || len(fields.List) == 0 {
+		return ""
+	}
+	return "[" + fieldTypes(fields) + "] "
+}
+
+// objectType returns the type of a declared object in typed mode qualified by package names, empty otherwise
+func (g *GoParser) objectType(n *ast.Ident) string {
+	if g.types == nil {
+		return ""
+	}
+
+	obj := g.types.info.Defs[n]
+	if obj == nil {
+		return ""
+	}
+
+	return types.TypeString(obj.Type(), func(pkg *types.Package) string {
+		if pkg == obj.Pkg() {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
+
+// API change kinds
+const (
+	APIRemoved = "removed"
+	APIChanged = "changed"
+	APIAdded   = "added"
+)
+
+// APIChange contains a symbol differing between two snapshots
+type APIChange struct {
+	Kind string
+	Name string
+
+	// Change is one of APIRemoved, APIChanged and APIAdded
+	Change string
+
+	Old string
+	New string
+
+	// Breaking is set for changes that can break users of the package: removed symbols, changed signatures
+	// and methods added to interfaces
+	Breaking bool
+}
+
+// CompareAPI returns changes between two snapshots of the API surface sorted by name;
+// snapshots are expected to be taken in the same mode, typed or not
+//
+//	old, _ := NewFromGit(".", "v1.2.0", "config/config.go")
+//	for _, c := range CompareAPI(APISurface(old), APISurface(p)) {
+//	    if c.Breaking { ... }
+//	}
+func CompareAPI(old, new *APISnapshot) []APIChange {
+	type key struct {
+		kind string
+		name string
+	}
+
+	oldSymbols := make(map[key]string, len(old.Symbols))
+	for _, s := range old.Symbols {
+		oldSymbols[key{s.Kind, s.Name}] = s.Signature
+	}
+
+	result := make([]APIChange, 0)
+
+	for _, s := range new.Symbols {
+		k := key{s.Kind, s.Name}
+
+		sig, ok := oldSymbols[k]
+		delete(oldSymbols, k)
+
+		switch {
+		case !ok:
+			result = append(result, APIChange{
+				Kind: s.Kind, Name: s.Name, Change: APIAdded, New: s.Signature,
+				Breaking: s.Kind == APIInterfaceMethod,
+			})
+		case sig != s.Signature:
+			result = append(result, APIChange{
+				Kind: s.Kind, Name: s.Name, Change: APIChanged, Old: sig, New: s.Signature,
+				Breaking: sig != "" && s.Signature != "", // an empty signature is unknown
+			})
+		}
+	}
+
+	for k, sig := range oldSymbols {
+		result = append(result, APIChange{Kind: k.kind, Name: k.name, Change: APIRemoved, Old: sig, Breaking: true})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Kind < b.Kind
+	})
+
+	return result
+}
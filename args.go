@@ -0,0 +1,140 @@
+package goparser
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ArgOwner is the label argument with the owner of a value, see Report.ByOwner
+//
+//	// parser owner=team-a
+//	var timeout = 30
+const ArgOwner = "owner"
+
+// splitLabelArgs splits trailing key=value arguments off the text of a label line,
+// the first argument may be attached to a single-word label with a colon
+//
+//	parser owner=team-a           // "parser", {"owner": "team-a"}
+//	parser:env=prod name=maxConns // "parser", {"env": "prod", "name": "maxConns"}
+//
+// arguments may also be listed in parentheses, see parenLabelArgs
+func splitLabelArgs(txt string) (string, map[string]string) {
+	if label, args, ok := parenLabelArgs(txt); ok {
+		return label, args
+	}
+
+	fields := strings.Fields(txt)
+
+	i := len(fields)
+	for i > 1 {
+		k, _, ok := strings.Cut(fields[i-1], "=")
+		if !ok || k == "" {
+			break
+		}
+		i--
+	}
+
+	var first string
+	if i == 1 {
+		fields[0], first = cutLabelArg(fields[0])
+	}
+
+	if i == len(fields) && first == "" {
+		return txt, nil
+	}
+
+	args := make(map[string]string, len(fields)-i+1)
+	for _, f := range append([]string{first}, fields[i:]...) {
+		if f == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(f, "=")
+		args[k] = v
+	}
+
+	return strings.Join(fields[:i], " "), args
+}
+
+// cutLabelArg cuts a key=value argument attached to the label with a colon, empty if there's none
+//
+//	parser:env=prod // "parser", "env=prod"
+//	cfg:db          // "cfg:db", ""
+func cutLabelArg(field string) (string, string) {
+	k, _, ok := strings.Cut(field, "=")
+	if !ok {
+		return field, ""
+	}
+
+	i := strings.LastIndexByte(k, ':')
+	if i <= 0 || i == len(k)-1 {
+		return field, ""
+	}
+
+	return field[:i], field[i+1:]
+}
+
+// parenLabelArgs parses comma-separated arguments in parentheses following a single-word label,
+// values may be quoted as Go strings; false if the text isn't of the form
+//
+//	parser(name="max_conns", secret=true) // "parser", {"name": "max_conns", "secret": "true"}
+func parenLabelArgs(txt string) (string, map[string]string, bool) {
+	txt = strings.TrimSpace(txt)
+
+	open := strings.IndexByte(txt, '(')
+	if open <= 0 || !strings.HasSuffix(txt, ")") || strings.ContainsFunc(txt[:open], unicode.IsSpace) {
+		return "", nil, false
+	}
+
+	label, rest := txt[:open], strings.TrimSpace(txt[open+1:len(txt)-1])
+
+	args := make(map[string]string)
+	for rest != "" {
+		// a key without a value is a flag, e.g. `parser(secret)`
+		end := strings.IndexAny(rest, "=,")
+		if end < 0 {
+			end = len(rest)
+		}
+
+		k := strings.TrimSpace(rest[:end])
+		if k == "" || strings.ContainsAny(k, "\"`") || strings.ContainsFunc(k, unicode.IsSpace) {
+			return "", nil, false
+		}
+
+		rest = rest[end:]
+		if !strings.HasPrefix(rest, "=") {
+			args[k] = ""
+			rest = strings.TrimSpace(strings.TrimPrefix(rest, ","))
+			continue
+		}
+
+		var v string
+
+		rest = strings.TrimSpace(rest[1:])
+		if rest != "" && (rest[0] == '"' || rest[0] == '`') {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return "", nil, false
+			}
+
+			v, _ = strconv.Unquote(quoted)
+			rest = strings.TrimSpace(rest[len(quoted):])
+			if rest != "" && rest[0] != ',' {
+				return "", nil, false
+			}
+		} else {
+			v, _, _ = strings.Cut(rest, ",")
+			rest = rest[len(v):]
+			v = strings.TrimSpace(v)
+		}
+
+		args[k] = v
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, ","))
+	}
+
+	if len(args) == 0 {
+		args = nil
+	}
+
+	return label, args, true
+}
@@ -0,0 +1,211 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// ExitCall contains a call of panic, log.Fatal* or os.Exit
+type ExitCall struct {
+	// Func is the name of the enclosing function, methods are named as "Type.Method"
+	Func string
+
+	// Call is the called function name, e.g. "panic" or "log.Fatalf"; packages are named by their import paths,
+	// not by aliases of the file
+	Call string
+
+	Pos token.Position
+}
+
+// GetExitCalls returns all calls of panic, log.Fatal* and os.Exit inside functions;
+// if filter isn't nil, only functions whose names (as "Func" or "Type.Method") it accepts are checked
+func GetExitCalls(g *GoParser, filter func(funcName string) bool) []ExitCall {
+	result := make([]ExitCall, 0)
+
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Body == nil {
+				continue
+			}
+
+			name := funcName(decl)
+			if filter != nil && !filter(name) {
+				continue
+			}
+
+			ast.Inspect(decl.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				if cName := f.callName(call); isExitCall(cName) {
+					result = append(result, ExitCall{
+						Func: name,
+						Call: cName,
+						Pos:  f.fset.Position(call.Pos()),
+					})
+				}
+
+				return true
+			})
+		}
+	}
+
+	return result
+}
+
+func isExitCall(name string) bool {
+	return name == "panic" || name == "os.Exit" || strings.HasPrefix(name, "log.Fatal")
+}
+
+// UnwrappedReturn contains a return site passing an error through without wrapping
+type UnwrappedReturn struct {
+	// Func is the name of the enclosing function, methods are named as "Type.Method"
+	Func string
+
+	// Expr is the returned expression, e.g. "err"
+	Expr string
+
+	Pos token.Position
+}
+
+// GetUnwrappedReturns returns return sites of functions returning error (or a type implementing it in typed mode)
+// that pass local error variables through as is, instead of wrapping them with fmt.Errorf("...: %w", err) or errors.Join;
+// returning nil, package level errors or function calls isn't reported, as well as variables last assigned
+// a call of fmt.Errorf, errors.Join or errors.New before the return:
+//
+//	err := fmt.Errorf("load: %w", e)
+//	return err // not reported
+//
+// the last assignment is the last one in the source before the return, branches and loops aren't taken into account;
+// if filter isn't nil, only functions whose names (as "Func" or "Type.Method") it accepts are checked
+func GetUnwrappedReturns(g *GoParser, filter func(funcName string) bool) []UnwrappedReturn {
+	result := make([]UnwrappedReturn, 0)
+
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Body == nil || !g.returnsError(decl.Type) {
+				continue
+			}
+
+			name := funcName(decl)
+			if filter != nil && !filter(name) {
+				continue
+			}
+
+			numResults := decl.Type.Results.NumFields()
+
+			ast.Inspect(decl.Body, func(n ast.Node) bool {
+				switch s := n.(type) {
+				case *ast.FuncLit:
+					return false // function literals have their own results
+				case *ast.ReturnStmt:
+					if len(s.Results) != numResults {
+						return true // bare return or a call returning a tuple
+					}
+
+					errExpr, ok := s.Results[numResults-1].(*ast.Ident)
+					if !ok || !isLocal(errExpr, decl) {
+						return true
+					}
+
+					if f.isErrorCall(lastAssigned(decl.Body, errExpr.Obj, s.Pos())) {
+						return true
+					}
+
+					result = append(result, UnwrappedReturn{
+						Func: name,
+						Expr: errExpr.Name,
+						Pos:  f.fset.Position(s.Pos()),
+					})
+				}
+
+				return true
+			})
+		}
+	}
+
+	return result
+}
+
+// returnsError reports whether the last function result is error, or a type implementing it in typed mode
+func (g *GoParser) returnsError(t *ast.FuncType) bool {
+	if t.Results == nil || len(t.Results.List) == 0 {
+		return false
+	}
+
+	return g.isErrorType(t.Results.List[len(t.Results.List)-1].Type)
+}
+
+// lastAssigned returns the value last assigned to the variable in the body before the position in the source,
+// nil if there's none or it's assigned a multi-value expression
+func lastAssigned(body *ast.BlockStmt, obj *ast.Object, pos token.Pos) ast.Expr {
+	var last ast.Expr
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.Pos() >= pos {
+			return false
+		}
+
+		switch s := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range s.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && id.Obj == obj {
+					last = nil
+					if len(s.Lhs) == len(s.Rhs) {
+						last = s.Rhs[i]
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, id := range s.Names {
+				if id.Obj == obj {
+					last = nil
+					if len(s.Names) == len(s.Values) {
+						last = s.Values[i]
+					}
+				}
+			}
+		}
+
+		return true
+	})
+
+	return last
+}
+
+// isErrorCall reports whether the expression is a call of fmt.Errorf, errors.Join or errors.New
+func (f *file) isErrorCall(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	switch {
+	case f.isPkgSelector(sel, f.importName("fmt")):
+		return sel.Sel.Name == "Errorf"
+	case f.isPkgSelector(sel, f.importName("errors")):
+		return sel.Sel.Name == "Join" || sel.Sel.Name == "New"
+	}
+
+	return false
+}
+
+// isLocal reports whether the identifier refers to a parameter, a result or a variable of the function
+func isLocal(id *ast.Ident, decl *ast.FuncDecl) bool {
+	if id.Obj == nil || id.Obj.Kind != ast.Var {
+		return false
+	}
+
+	pos := id.Obj.Pos()
+	return pos.IsValid() && pos >= decl.Pos() && pos < decl.End()
+}
@@ -0,0 +1,117 @@
+package goparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetUnwrappedReturns(t *testing.T) {
+	p := newTestParser(t, `package p
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errBase = errors.New("base")
+
+func load() error { return nil }
+
+func wrapped() error {
+	err := load()
+	if err != nil {
+		err := fmt.Errorf("x: %w", err)
+		return err
+	}
+	return nil
+}
+
+func joined(a, b error) error {
+	err := errors.Join(a, b)
+	return err
+}
+
+func created() error {
+	var err = errors.New("x")
+	return err
+}
+
+func passed() error {
+	err := load()
+	return err
+}
+
+func reassigned() error {
+	err := fmt.Errorf("x: %w", errBase)
+	err = load()
+	return err
+}
+
+func param(err error) error {
+	return err
+}
+
+func multi() (int, error) {
+	n, err := 1, load()
+	_, err = 0, fmt.Errorf("%w", err)
+	return n, err
+}
+
+func base() error {
+	return errBase
+}
+`)
+
+	got := make([]string, 0)
+	for _, r := range GetUnwrappedReturns(p, nil) {
+		got = append(got, r.Func)
+	}
+
+	want := []string{"passed", "reassigned", "param"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestGetExitCalls(t *testing.T) {
+	p := newTestParser(t, `package p
+
+import (
+	l "log"
+	"os"
+)
+
+type logger struct{}
+
+func (logger) Fatal(v ...any) {}
+
+func (logger) Exit(code int) {}
+
+func run() {
+	panic("a")
+	l.Fatalf("b")
+	os.Exit(1)
+}
+
+func shadowed() {
+	var log logger
+	log.Fatal("c")
+
+	panic := func(string) {}
+	panic("d")
+
+	os := logger{}
+	os.Exit(2)
+}
+`)
+
+	got := make([]string, 0)
+	for _, c := range GetExitCalls(p, nil) {
+		got = append(got, c.Func+":"+c.Call)
+	}
+
+	want := []string{"run:panic", "run:log.Fatalf", "run:os.Exit"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
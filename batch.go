@@ -0,0 +1,170 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+)
+
+// parseBasicLits parses elements of a homogeneous composite literal (e.g. a large table)
+// with a single type switch for all of them instead of one per element;
+// ok is false if some element isn't a basic literal of the type, the caller should fall back to parseBasicLit then
+func parseBasicLits[V iLit](elts []ast.Expr) (values []V, ok bool) {
+	values = make([]V, len(elts))
+
+	switch vs := (interface{})(values).(type) {
+	case []string:
+		ok = parseStrings(vs, elts)
+	case []bool:
+		ok = parseBools(vs, elts)
+	case []int:
+		ok = parseInts(vs, elts, strconv.IntSize)
+	case []int8:
+		ok = parseInts(vs, elts, 8)
+	case []int16:
+		ok = parseInts(vs, elts, 16)
+	case []int32:
+		ok = parseInts(vs, elts, 32)
+	case []int64:
+		ok = parseInts(vs, elts, 64)
+	case []uint:
+		ok = parseUints(vs, elts, strconv.IntSize)
+	case []uintptr:
+		ok = parseUints(vs, elts, strconv.IntSize)
+	case []uint8:
+		ok = parseUints(vs, elts, 8)
+	case []uint16:
+		ok = parseUints(vs, elts, 16)
+	case []uint32:
+		ok = parseUints(vs, elts, 32)
+	case []uint64:
+		ok = parseUints(vs, elts, 64)
+	case []float32:
+		ok = parseFloats(vs, elts, 32)
+	case []float64:
+		ok = parseFloats(vs, elts, 64)
+	}
+
+	if !ok {
+		return nil, false
+	}
+
+	return values, true
+}
+
+func parseStrings(dst []string, elts []ast.Expr) bool {
+	for i, elt := range elts {
+		lit, ok := basicLit(elt)
+		if !ok || lit.Kind != token.STRING {
+			return false
+		}
+
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return false
+		}
+
+		dst[i] = s
+	}
+
+	return true
+}
+
+func parseBools(dst []bool, elts []ast.Expr) bool {
+	for i, elt := range elts {
+		id, ok := ast.Unparen(elt).(*ast.Ident)
+		if !ok {
+			return false
+		}
+
+		b, ok := boolIdent(id)
+		if !ok {
+			return false
+		}
+
+		dst[i] = b
+	}
+
+	return true
+}
+
+func parseInts[I iInt](dst []I, elts []ast.Expr, bitSize int) bool {
+	for i, elt := range elts {
+		lit, ok := basicLit(elt)
+		if !ok {
+			return false
+		}
+
+		s, ok := intLitValue(lit)
+		if !ok {
+			return false
+		}
+
+		v, err := strconv.ParseInt(s, 0, bitSize)
+		if err != nil {
+			return false
+		}
+
+		dst[i] = I(v)
+	}
+
+	return true
+}
+
+func parseUints[I iInt](dst []I, elts []ast.Expr, bitSize int) bool {
+	for i, elt := range elts {
+		lit, ok := basicLit(elt)
+		if !ok {
+			return false
+		}
+
+		s, ok := intLitValue(lit)
+		if !ok {
+			return false
+		}
+
+		v, err := strconv.ParseUint(s, 0, bitSize)
+		if err != nil {
+			return false
+		}
+
+		dst[i] = I(v)
+	}
+
+	return true
+}
+
+func parseFloats[F iFloat](dst []F, elts []ast.Expr, bitSize int) bool {
+	for i, elt := range elts {
+		lit, ok := basicLit(elt)
+		if !ok || lit.Kind != token.FLOAT {
+			return false
+		}
+
+		v, err := strconv.ParseFloat(lit.Value, bitSize)
+		if err != nil {
+			return false
+		}
+
+		dst[i] = F(v)
+	}
+
+	return true
+}
+
+// splitKeyValues returns keys and values of a composite literal if all its elements are key-value pairs
+func splitKeyValues(elts []ast.Expr) (keys, values []ast.Expr, ok bool) {
+	keys = make([]ast.Expr, len(elts))
+	values = make([]ast.Expr, len(elts))
+
+	for i, elt := range elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, nil, false
+		}
+
+		keys[i], values[i] = kv.Key, kv.Value
+	}
+
+	return keys, values, true
+}
@@ -0,0 +1,132 @@
+package goparser
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tableSize is the number of entries of generated literal tables, see tableSource
+const tableSize = 100_000
+
+// tableSource returns the source of a labeled map[string]int64 literal with n entries
+func tableSource(n int) string {
+	var sb strings.Builder
+	sb.WriteString("package p\n\n// table\nvar table = map[string]int64{\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "\t\"key%d\": %d,\n", i, i*7)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// tableElts returns keys and values of the generated table
+func tableElts(tb testing.TB, p *GoParser) (keys, values []ast.Expr) {
+	tb.Helper()
+
+	var elts []ast.Expr
+	walkDecls(p, map[string]struct{}{"table": {}}, func(_ DeclInfo, val ast.Expr) *struct{} {
+		elts = val.(*ast.CompositeLit).Elts
+		return nil
+	})
+
+	keys, values, ok := splitKeyValues(elts)
+	if !ok {
+		tb.Fatal("table isn't a keyed literal")
+	}
+
+	return keys, values
+}
+
+func TestParseBasicLits(t *testing.T) {
+	p := newTestParser(t, tableSource(1000))
+	keys, values := tableElts(t, p)
+
+	tests := []struct {
+		name string
+		elts []ast.Expr
+		got  func([]ast.Expr) (any, bool)
+		want func([]ast.Expr) any
+	}{
+		{
+			name: "strings",
+			elts: keys,
+			got:  func(elts []ast.Expr) (any, bool) { return parseBasicLits[string](elts) },
+			want: func(elts []ast.Expr) any { return parseEach[string](elts) },
+		},
+		{
+			name: "int64",
+			elts: values,
+			got:  func(elts []ast.Expr) (any, bool) { return parseBasicLits[int64](elts) },
+			want: func(elts []ast.Expr) any { return parseEach[int64](elts) },
+		},
+		{
+			name: "mismatched type",
+			elts: keys,
+			got: func(elts []ast.Expr) (any, bool) {
+				v, ok := parseBasicLits[int64](elts)
+				return v, !ok
+			},
+			want: func([]ast.Expr) any { return []int64(nil) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.got(tt.elts)
+			if !ok {
+				t.Fatal("unexpected result of the fast path")
+			}
+
+			if want := tt.want(tt.elts); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// parseEach parses the elements one by one like the per-element path of composites
+func parseEach[V iLit](elts []ast.Expr) []V {
+	res := make([]V, 0, len(elts))
+	for _, elt := range elts {
+		if v, ok := parseElt[V](elt); ok && v != nil {
+			res = append(res, *v)
+		}
+	}
+	return res
+}
+
+func BenchmarkGetMapValues(b *testing.B) {
+	p := newTestParser(b, tableSource(tableSize))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if values := GetMapValues[string, int64](p, "table"); len(values) != 1 || len(values[0].Value) != tableSize {
+			b.Fatalf("got %d values", len(values))
+		}
+	}
+}
+
+// BenchmarkParseBasicLits compares the fast path for homogeneous literal tables with parsing elements one by one
+func BenchmarkParseBasicLits(b *testing.B) {
+	p := newTestParser(b, tableSource(tableSize))
+	keys, values := tableElts(b, p)
+
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			parseBasicLits[string](keys)
+			parseBasicLits[int64](values)
+		}
+	})
+
+	b.Run("each", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			parseEach[string](keys)
+			parseEach[int64](values)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package goparser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// BuildVar contains a labeled build info variable
+type BuildVar struct {
+	LitValue[string]
+
+	// Symbol is the fully qualified variable name for the -X linker flag, e.g. main.version
+	// or example.com/app/internal/build.version
+	Symbol string
+
+	// Suggested is a value suggested for the linker flag:
+	// a shell command substitution for well-known names (version, commit, date), the current value otherwise
+	Suggested string
+}
+
+// BuildInfo contains labeled build info variables and a suggested -ldflags string
+type BuildInfo struct {
+	Vars []BuildVar
+
+	// LDFlags is meant to be used as `go build -ldflags "<LDFlags>"`, values are escaped for the shell double quotes;
+	// variables which values contain both single and double quotes can't be set by the linker and are left out
+	LDFlags string
+}
+
+// GetBuildInfo collects labeled string variables (version, commit, build date, etc.)
+// and suggests -ldflags to set them at link time;
+// pkgPath is the import path of the parsed package; if it is empty, main is used for the main package,
+// the import path set by WithModule for other ones or the package name if it isn't set
+//
+//	// parser:build
+//	var version = "dev"
+func GetBuildInfo(g *GoParser, pkgPath string, docLabels ...string) BuildInfo {
+	vars := make(map[string]struct{})
+	pkgNames := make(map[string]string, len(g.files))
+	for _, f := range g.files {
+		for name := range varNames(f.ast) {
+			vars[name] = struct{}{}
+		}
+		pkgNames[f.path] = f.ast.Name.Name
+	}
+
+	var result BuildInfo
+	flags := make([]string, 0)
+
+	for _, v := range buildValues(g, docLabels) {
+		if _, ok := vars[v.Name]; !ok {
+			continue // constants can't be set by the linker
+		}
+
+		symbol := pkgPath
+		if symbol == "" {
+			switch name := pkgNames[v.Pos.Filename]; {
+			case name == "main":
+				symbol = name
+			case v.Package != "":
+				symbol = v.Package
+			default:
+				symbol = name
+			}
+		}
+
+		bv := BuildVar{
+			LitValue:  v,
+			Symbol:    symbol + "." + v.Name,
+			Suggested: v.Value,
+		}
+
+		cmd := buildCommand(v.Name)
+		if cmd != "" {
+			bv.Suggested = cmd
+		}
+
+		result.Vars = append(result.Vars, bv)
+
+		if flag, ok := ldflag(bv.Symbol, bv.Suggested, cmd != ""); ok {
+			flags = append(flags, flag)
+		}
+	}
+
+	result.LDFlags = strings.Join(flags, " ")
+
+	return result
+}
+
+// buildValues returns labeled string values and labeled string variables declared without a value,
+// the usual targets of -X linker flags:
+//
+//	// parser:build
+//	var commit string
+func buildValues(g *GoParser, docLabels []string) []LitValue[string] {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	result := make([]LitValue[string], 0)
+	toValue := basicValue[string](g)
+
+	g.walkLabeled(docMap, func(_ *file, info DeclInfo, val ast.Expr) {
+		if v := toValue(info, val); v != nil {
+			result = append(result, *v)
+		}
+	}, func(info DeclInfo, kind, _ string) {
+		if kind == DiagNoValue && info.Func == "" && info.Type == "string" {
+			result = append(result, LitValue[string]{DeclInfo: info})
+		}
+	})
+
+	return result
+}
+
+// String returns a human-readable report
+func (b BuildInfo) String() string {
+	var sb strings.Builder
+	for _, v := range b.Vars {
+		sb.WriteString(fmt.Sprintf("%s = %q (suggested: %s)\n", v.Symbol, v.Value, v.Suggested))
+	}
+	sb.WriteString(fmt.Sprintf("-ldflags \"%s\"\n", b.LDFlags))
+	return sb.String()
+}
+
+// ldflag returns the -X linker flag setting the symbol to the value, escaped to be written in shell double quotes;
+// command substitutions are kept as is. The linker flag is quoted with single or double quotes,
+// ok is false if the value contains both
+func ldflag(symbol, value string, command bool) (flag string, ok bool) {
+	quote := "'"
+	if strings.Contains(value, "'") {
+		if strings.Contains(value, `"`) {
+			return "", false
+		}
+		quote = `\"`
+	}
+
+	if !command {
+		value = shellEscaper.Replace(value)
+	}
+
+	return "-X " + quote + symbol + "=" + value + quote, true
+}
+
+// shellEscaper escapes characters special in shell double quotes
+var shellEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+
+// buildCommand suggests a shell command substitution by words of the variable name,
+// e.g. "build" and "date" of buildDate; it returns an empty string for other names
+func buildCommand(name string) string {
+	for _, w := range nameWords(name) {
+		switch w {
+		case "commit", "revision", "sha":
+			return "$(git rev-parse HEAD)"
+		case "date", "time", "timestamp":
+			return "$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+		case "version", "tag":
+			return "$(git describe --tags --always)"
+		}
+	}
+
+	return ""
+}
+
+// nameWords splits a camelCase or snake_case name into lower-case words, acronyms are kept whole:
+//
+//	gitSHA       // "git", "sha"
+//	BUILD_TIME   // "build", "time"
+//	HTTPTimeout  // "http", "timeout"
+func nameWords(name string) []string {
+	runes := []rune(name)
+	words := make([]string, 0)
+	start := 0
+
+	for i := 0; i <= len(runes); i++ {
+		split := i == len(runes) || runes[i] == '_' || runes[i] == '-'
+		if !split && i > start && unicode.IsUpper(runes[i]) {
+			// fooBar or the last capital of an acronym followed by a word: HTTPServer
+			split = unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		}
+
+		if !split {
+			continue
+		}
+
+		if i > start {
+			words = append(words, strings.ToLower(string(runes[start:i])))
+		}
+
+		start = i
+		if i < len(runes) && (runes[i] == '_' || runes[i] == '-') {
+			start++
+		}
+	}
+
+	return words
+}
+
+// varNames returns names of package level variables
+func varNames(f *ast.File) map[string]struct{} {
+	result := make(map[string]struct{})
+
+	for _, d := range f.Decls {
+		decl, ok := d.(*ast.GenDecl)
+		if !ok || decl.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range decl.Specs {
+			s, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			for _, n := range s.Names {
+				result[n.Name] = struct{}{}
+			}
+		}
+	}
+
+	return result
+}
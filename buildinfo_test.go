@@ -0,0 +1,138 @@
+package goparser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGetBuildInfo(t *testing.T) {
+	p := newTestParser(t, `package main
+
+// parser:build
+var version = "dev"
+
+// parser:build
+var commit string
+
+// parser:build
+var timeout = "30s"
+
+// parser:build
+const name = "app"
+
+// parser:build
+var stage, shard string
+
+// parser:build
+var count int
+`)
+
+	info := GetBuildInfo(p, "", "parser:build")
+
+	got := make(map[string]string)
+	for _, v := range info.Vars {
+		got[v.Symbol] = v.Suggested
+	}
+
+	want := map[string]string{
+		"main.version": "$(git describe --tags --always)",
+		"main.commit":  "$(git rev-parse HEAD)",
+		"main.timeout": "30s",
+		"main.stage":   "",
+		"main.shard":   "",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestGetBuildInfoSymbol(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "build", "build.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("package build\n\n// parser:build\nvar version = \"dev\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		pkgPath string
+		want    string
+	}{
+		{name: "package name", want: "build.version"},
+		{name: "module", opts: []Option{WithModule()}, want: "example.com/app/build.version"},
+		{name: "explicit", opts: []Option{WithModule()}, pkgPath: "example.com/other", want: "example.com/other.version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(path, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			info := GetBuildInfo(p, tt.pkgPath, "parser:build")
+			if len(info.Vars) != 1 || info.Vars[0].Symbol != tt.want {
+				t.Fatalf("got %+v, want symbol %s", info.Vars, tt.want)
+			}
+		})
+	}
+}
+
+func TestLDFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		command bool
+		want    string
+		wantOk  bool
+	}{
+		{name: "plain", value: "1.0", want: `-X 'main.v=1.0'`, wantOk: true},
+		{name: "command", value: "$(git rev-parse HEAD)", command: true, want: `-X 'main.v=$(git rev-parse HEAD)'`, wantOk: true},
+		{name: "shell characters", value: "a$b`c\\d\"e", want: "-X 'main.v=a\\$b\\`c\\\\d\\\"e'", wantOk: true},
+		{name: "single quote", value: "it's", want: `-X \"main.v=it's\"`, wantOk: true},
+		{name: "both quotes", value: `it's "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ldflag("main.v", tt.value, tt.command)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("got %s, %v, want %s, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestNameWords(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "commit", want: []string{"commit"}},
+		{name: "buildDate", want: []string{"build", "date"}},
+		{name: "gitSHA", want: []string{"git", "sha"}},
+		{name: "BUILD_TIME", want: []string{"build", "time"}},
+		{name: "HTTPTimeout", want: []string{"http", "timeout"}},
+		{name: "appVersion2", want: []string{"app", "version2"}},
+		{name: "stage", want: []string{"stage"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nameWords(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package goparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// ChangeKind is a kind of a value change
+type ChangeKind string
+
+// Change kinds
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeUpdated ChangeKind = "updated"
+)
+
+// Change contains a difference of a value between two reports
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	Name string     `json:"name"`
+	File string     `json:"file"`
+
+	// Func is the enclosing function of a value declared in a function body ("Type.Method" for methods)
+	Func string `json:"func,omitempty"`
+
+	// Old and New are the values formatted as Go source, Old is empty for added values and New for removed ones
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// String returns a human-readable description of the change
+//
+//	maxRetries: 3 → 5 in config/defaults.go
+//	Client.retry.backoff: 1 → 2 in client.go
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s: added %s in %s", c.qualifiedName(), c.New, c.File)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s: removed %s from %s", c.qualifiedName(), c.Old, c.File)
+	}
+
+	return fmt.Sprintf("%s: %s → %s in %s", c.qualifiedName(), c.Old, c.New, c.File)
+}
+
+// qualifiedName returns the name of the value qualified by the enclosing function if there is one
+func (c Change) qualifiedName() string {
+	if c.Func == "" {
+		return c.Name
+	}
+	return c.Func + "." + c.Name
+}
+
+// DiffOption configures Diff
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	values map[reflect.Type]func(old, new any) bool
+	kinds  map[string]func(old, new Result) bool
+}
+
+// WithValueComparer sets a function comparing values of the type, including elements of slices and maps,
+// e.g. to ignore insignificant differences of floats; other values are compared with reflect.DeepEqual
+func WithValueComparer[T any](equal func(old, new T) bool) DiffOption {
+	return func(o *diffOptions) {
+		if o.values == nil {
+			o.values = make(map[reflect.Type]func(old, new any) bool)
+		}
+		o.values[reflect.TypeOf((*T)(nil)).Elem()] = func(old, new any) bool {
+			return equal(old.(T), new.(T))
+		}
+	}
+}
+
+// WithKindComparer sets a function comparing results of the kind, e.g. to compare selector references
+// like time.Second semantically; it replaces comparison of values
+func WithKindComparer(kind string, equal func(old, new Result) bool) DiffOption {
+	return func(o *diffOptions) {
+		if o.kinds == nil {
+			o.kinds = make(map[string]func(old, new Result) bool)
+		}
+		o.kinds[kind] = equal
+	}
+}
+
+// WithFloatTolerance makes floats differing by at most the tolerance equal
+func WithFloatTolerance(tolerance float64) DiffOption {
+	return WithValueComparer(func(old, new float64) bool {
+		return math.Abs(old-new) <= tolerance
+	})
+}
+
+// equal reports whether the results are equal using the comparers
+func (o *diffOptions) equal(old, new Result) bool {
+	if old.Kind != new.Kind {
+		return false
+	}
+
+	if equal, ok := o.kinds[old.Kind]; ok {
+		return equal(old, new)
+	}
+
+	return o.equalValues(old.Value, new.Value)
+}
+
+func (o *diffOptions) equalValues(old, new any) bool {
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(new)
+	if !ov.IsValid() || !nv.IsValid() || ov.Type() != nv.Type() {
+		return reflect.DeepEqual(old, new)
+	}
+
+	if equal, ok := o.values[ov.Type()]; ok {
+		return equal(old, new)
+	}
+
+	// formats of entries are ignored as formats of basic values are
+	if e, ok := old.(Entry); ok {
+		ne := new.(Entry)
+		return o.equalValues(e.Key, ne.Key) && o.equalValues(e.Value, ne.Value)
+	}
+
+	if ov.Kind() == reflect.Slice {
+		if ov.Len() != nv.Len() {
+			return false
+		}
+		for i := 0; i < ov.Len(); i++ {
+			if !o.equalValues(ov.Index(i).Interface(), nv.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(old, new)
+}
+
+// Diff returns changes of values between two reports, e.g. of two revisions (see CollectValues and NewFromGit);
+// values are matched by file, enclosing function and name, changes of labels and docs only are ignored;
+// changes are sorted by file, function and name. Values are compared with reflect.DeepEqual unless comparers are set:
+//
+//	changes := Diff(old, new, WithFloatTolerance(1e-9), WithKindComparer(KindSelectorRef, sameDuration))
+func Diff(old, new *Report, opts ...DiffOption) []Change {
+	o := &diffOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	type key struct {
+		file string
+		fn   string
+		name string
+	}
+
+	oldResults := make(map[key]Result, len(old.Results))
+	for _, r := range old.Results {
+		oldResults[key{r.File, r.Func, r.Name}] = r
+	}
+
+	changes := make([]Change, 0)
+
+	for _, r := range new.Results {
+		k := key{r.File, r.Func, r.Name}
+
+		prev, ok := oldResults[k]
+		delete(oldResults, k)
+
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: ChangeAdded, Name: r.Name, File: r.File, Func: r.Func, New: displayValue(r)})
+		case !o.equal(prev, r):
+			changes = append(changes, Change{Kind: ChangeUpdated, Name: r.Name, File: r.File, Func: r.Func, Old: displayValue(prev), New: displayValue(r)})
+		}
+	}
+
+	for _, prev := range oldResults {
+		changes = append(changes, Change{Kind: ChangeRemoved, Name: prev.Name, File: prev.File, Func: prev.Func, Old: displayValue(prev)})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].File != changes[j].File {
+			return changes[i].File < changes[j].File
+		}
+		if changes[i].Func != changes[j].Func {
+			return changes[i].Func < changes[j].Func
+		}
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes
+}
+
+// DiffParsers returns changes of labeled values (see CollectValues) between two parsers, e.g. of two revisions;
+// use Diff with reports of CollectValues to set comparers
+func DiffParsers(old, new *GoParser, labels ...string) []Change {
+	return Diff(CollectValues(old, labels...), CollectValues(new, labels...))
+}
+
+// displayValue returns the value formatted as Go source keeping the integer format
+func displayValue(r Result) string {
+	if r.Kind == KindSelectorRef || r.Kind == KindAlloc {
+		return fmt.Sprint(r.Value)
+	}
+
+	lit, _, err := goLiteral(r.Value)
+	if err != nil {
+		return fmt.Sprint(r.Value)
+	}
+
+	if r.Format != nil {
+		lit = formatIntLit(r.Value, *r.Format, lit)
+	}
+
+	return lit
+}
+
+// WriteChangelog writes the changes as a Markdown list
+//
+//	- `maxRetries`: 3 → 5 in config/defaults.go
+func WriteChangelog(w io.Writer, changes []Change) error {
+	for _, c := range changes {
+		var err error
+
+		switch c.Kind {
+		case ChangeAdded:
+			_, err = fmt.Fprintf(w, "- `%s`: added `%s` in %s\n", c.qualifiedName(), c.New, c.File)
+		case ChangeRemoved:
+			_, err = fmt.Fprintf(w, "- `%s`: removed `%s` from %s\n", c.qualifiedName(), c.Old, c.File)
+		default:
+			_, err = fmt.Fprintf(w, "- `%s`: `%s` → `%s` in %s\n", c.qualifiedName(), c.Old, c.New, c.File)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteChangelogJSON writes the changes as indented JSON
+func WriteChangelogJSON(w io.Writer, changes []Change) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(changes)
+}
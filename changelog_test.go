@@ -0,0 +1,29 @@
+package goparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffFuncLocal(t *testing.T) {
+	result := func(fn string, value int64) Result {
+		return Result{Kind: KindBasic, Name: "limit", File: "a.go", Func: fn, Value: value}
+	}
+
+	old := &Report{Results: []Result{result("", 1), result("run", 2), result("Client.retry", 3)}}
+	new := &Report{Results: []Result{result("", 1), result("run", 2), result("Client.retry", 5), result("stop", 7)}}
+
+	got := make([]string, 0)
+	for _, c := range Diff(old, new) {
+		got = append(got, c.String())
+	}
+
+	want := []string{
+		"Client.retry.limit: 3 → 5 in a.go",
+		"stop.limit: added 7 in a.go",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
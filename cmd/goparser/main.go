@@ -0,0 +1,241 @@
+// Command goparser extracts labeled values and runs audits on Go files
+//
+//	goparser values [flags] <file|dir> <label>...
+//	goparser values -out <label>=<file> [flags] <file|dir> [label...]
+//	goparser audit [flags] <file|dir>
+//	goparser serve [-addr :8080] [-root dir]
+//	goparser verify <dir>
+//
+// Exit codes: 0 - ok, 1 - findings reported by audit or out of date generated files, 2 - parse errors, 3 - usage errors,
+// 4 - errors writing results
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	gp "github.com/goiste/goparser"
+	"github.com/goiste/goparser/serve"
+)
+
+// exit codes
+const (
+	exitOK = iota
+	exitFindings
+	exitParseError
+	exitUsage
+	exitError
+)
+
+const usage = `usage:
+	goparser values [flags] <file|dir> <label>...
+	goparser values -out <label>=<file> [flags] <file|dir> [label...]
+	goparser audit [flags] <file|dir>
+	goparser serve [-addr :8080] [-root dir]
+	goparser verify <dir>
+`
+
+// summary contains counts per category printed by -summary-json
+type summary struct {
+	Values           int `json:"values"`
+	ExitCalls        int `json:"exitCalls"`
+	UnwrappedReturns int `json:"unwrappedReturns"`
+	ParseErrors      int `json:"parseErrors"`
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	cmd := args[0]
+
+	if cmd == "serve" {
+		return runServe(args[1:], stderr)
+	}
+
+	if cmd == "verify" {
+		return runVerify(args[1:], stdout, stderr)
+	}
+
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var include, exclude stringList
+	fs.Var(&include, "include", "glob `pattern` of files to parse in a directory, repeatable")
+	fs.Var(&exclude, "exclude", "glob `pattern` of files to skip in a directory, repeatable")
+	summaryJSON := fs.Bool("summary-json", false, "print counts per category as JSON instead of results")
+
+	var outs stringList
+	fs.Var(&outs, "out", "write values with a label to a .json, .jsonl, .yaml or .pb file, `label=file`, repeatable")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	if fs.NArg() < 1 || (cmd == "values" && fs.NArg() < 2 && len(outs) == 0) || (cmd != "values" && cmd != "audit") {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	routes := make([]gp.Route, 0, len(outs))
+	for _, o := range outs {
+		rt, err := gp.ParseRoute(o)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		routes = append(routes, rt)
+	}
+
+	var sum summary
+
+	p, err := newParser(fs.Arg(0), gp.WithInclude(include...), gp.WithExclude(exclude...))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		sum.ParseErrors++
+		printSummary(stdout, sum, *summaryJSON)
+		return exitParseError
+	}
+
+	code := exitOK
+
+	switch cmd {
+	case "values":
+		labels := fs.Args()[1:]
+
+		// a single scan for printed and routed labels
+		all := append([]string(nil), labels...)
+		for _, rt := range routes {
+			all = append(all, rt.Label)
+		}
+
+		report := gp.CollectValues(p, all...)
+		sum.Values = len(report.Results)
+
+		if err = report.WriteRoutes(routes); err != nil {
+			fmt.Fprintln(stderr, err)
+			code = exitError
+		}
+
+		if !*summaryJSON && len(labels) > 0 {
+			if err = report.Filter(labels...).WriteJSON(stdout); err != nil {
+				fmt.Fprintln(stderr, err)
+				code = exitError
+			}
+		}
+	case "audit":
+		exits := gp.GetExitCalls(p, nil)
+		unwrapped := gp.GetUnwrappedReturns(p, nil)
+		sum.ExitCalls, sum.UnwrappedReturns = len(exits), len(unwrapped)
+
+		if !*summaryJSON {
+			for _, c := range exits {
+				fmt.Fprintf(stdout, "%s: %s called in %s\n", c.Pos, c.Call, c.Func)
+			}
+			for _, r := range unwrapped {
+				fmt.Fprintf(stdout, "%s: %s returned without wrapping in %s\n", r.Pos, r.Expr, r.Func)
+			}
+		}
+
+		if len(exits)+len(unwrapped) > 0 {
+			code = exitFindings
+		}
+	}
+
+	printSummary(stdout, sum, *summaryJSON)
+
+	return code
+}
+
+// runServe serves extraction requests over HTTP until the server fails
+func runServe(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	addr := fs.String("addr", ":8080", "`address` to listen on")
+	root := fs.String("root", "", "`directory` request paths are resolved in, paths are rejected if it's empty")
+
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	if err := http.ListenAndServe(*addr, &serve.Server{Root: *root}); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// runVerify reports generated files of the directory that are out of date
+func runVerify(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	code := exitOK
+
+	for _, st := range gp.VerifyGenerated(args[0]) {
+		if st.Err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", st.File, st.Err)
+			code = exitParseError
+			continue
+		}
+
+		for _, src := range st.Sources {
+			fmt.Fprintf(stdout, "%s: out of date, %s %s\n", st.File, src.Name, src.Reason)
+		}
+
+		if code == exitOK {
+			code = exitFindings
+		}
+	}
+
+	return code
+}
+
+func newParser(path string, opts ...gp.Option) (*gp.GoParser, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if stat.IsDir() {
+		return gp.NewFromDir(path, opts...)
+	}
+
+	return gp.New(path, opts...)
+}
+
+func printSummary(w io.Writer, sum summary, enabled bool) {
+	if !enabled {
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(sum)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunValuesWriteError(t *testing.T) {
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "p.go")
+	if err := os.WriteFile(src, []byte("package p\n\n// cfg\nvar port = 80\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		out  string
+		want int
+	}{
+		{name: "written", out: filepath.Join(dir, "cfg.json"), want: exitOK},
+		{name: "missing directory", out: filepath.Join(dir, "missing", "cfg.json"), want: exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run([]string{"values", "-out", "cfg=" + tt.out, src}, &stdout, &stderr)
+			if code != tt.want {
+				t.Errorf("got exit code %d, want %d; stderr: %s", code, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunServeListenError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var stdout, stderr bytes.Buffer
+
+	// the address is in use, so the server can't listen on it
+	code := run([]string{"serve", "-addr", l.Addr().String()}, &stdout, &stderr)
+	if code != exitError {
+		t.Errorf("got exit code %d, want %d; stderr: %s", code, exitError, stderr.String())
+	}
+}
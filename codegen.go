@@ -0,0 +1,561 @@
+package goparser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// stdImports contains standard library packages that are imported automatically when used in generated code
+var stdImports = map[string]string{
+	"bytes":   "bytes",
+	"context": "context",
+	"errors":  "errors",
+	"expvar":  "expvar",
+	"fmt":     "fmt",
+	"json":    "encoding/json",
+	"math":    "math",
+	"os":      "os",
+	"sort":    "sort",
+	"strconv": "strconv",
+	"strings": "strings",
+	"sync":    "sync",
+	"time":    "time",
+}
+
+// GeneratedMarker is the standard comment marking generated files, added to all generated files
+// unless the header already contains a marker
+const GeneratedMarker = "// Code generated by goparser; DO NOT EDIT."
+
+var generatedMarker = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// GenFile is a Go source file being generated
+type GenFile struct {
+	pkg     string
+	imports map[string]string // path: name
+	body    bytes.Buffer
+	opts    *options
+	sources []GenSource // see GenManifest
+}
+
+// NewGenFile returns a new generated file of the package; WithFormatter and WithHeader options are respected
+func NewGenFile(pkg string, opts ...Option) *GenFile {
+	return &GenFile{
+		pkg:     pkg,
+		imports: make(map[string]string),
+		opts:    newOptions(opts),
+	}
+}
+
+// Import registers an import and returns the name to qualify its identifiers with;
+// an alias is chosen if the package name conflicts with another import
+//
+//	name := f.Import("github.com/google/uuid")
+//	f.Printf("var id = %s.New()\n", name)
+func (f *GenFile) Import(path string) string {
+	if name, ok := f.imports[path]; ok {
+		return name
+	}
+
+	base := importName(path)
+	name := base
+	for i := 2; f.nameTaken(name); i++ {
+		name = base + strconv.Itoa(i)
+	}
+
+	f.imports[path] = name
+
+	return name
+}
+
+func (f *GenFile) nameTaken(name string) bool {
+	for _, n := range f.imports {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Printf appends formatted code to the file body
+func (f *GenFile) Printf(format string, args ...any) {
+	fmt.Fprintf(&f.body, format, args...)
+}
+
+// Bytes returns the formatted source of the file;
+// like goimports, unused imports are pruned, used standard library packages are added
+// and imports are grouped into standard library and other ones
+func (f *GenFile) Bytes() ([]byte, error) {
+	used, err := f.usedNames()
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make(map[string]string, len(f.imports))
+	for path, name := range f.imports {
+		if _, ok := used[name]; ok {
+			imports[path] = name
+		}
+	}
+
+	for name := range used {
+		path, ok := stdImports[name]
+		if !ok || f.nameTaken(name) {
+			continue
+		}
+		imports[path] = name
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(f.header())
+	fmt.Fprintf(&buf, "package %s\n\n", f.pkg)
+
+	std, other := make([]string, 0), make([]string, 0)
+	for path := range imports {
+		if isStdImport(path) {
+			std = append(std, path)
+		} else {
+			other = append(other, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	if len(imports) > 0 {
+		buf.WriteString("import (\n")
+		for i, group := range [][]string{std, other} {
+			if i > 0 && len(std) > 0 && len(other) > 0 {
+				buf.WriteString("\n")
+			}
+			for _, path := range group {
+				if name := imports[path]; needsAlias(path, name) {
+					fmt.Fprintf(&buf, "\t%s %q\n", name, path)
+				} else {
+					fmt.Fprintf(&buf, "\t%q\n", path)
+				}
+			}
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	buf.Write(f.body.Bytes())
+
+	manifest, err := f.manifest()
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(manifest)
+
+	return f.opts.format(buf.Bytes())
+}
+
+// header returns header comments with the generated code marker, separated from the package clause
+func (f *GenFile) header() string {
+	var sb strings.Builder
+
+	if h := strings.TrimSpace(f.opts.header); h != "" {
+		for _, line := range strings.Split(h, "\n") {
+			line = strings.TrimRight(line, " \t")
+			if !strings.HasPrefix(line, "//") {
+				line = strings.TrimRight("// "+line, " ")
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if !generatedMarker.MatchString(sb.String()) {
+		sb.WriteString(GeneratedMarker + "\n\n")
+	}
+
+	return sb.String()
+}
+
+// usedNames returns names used as qualifiers in the body that aren't declared in it
+func (f *GenFile) usedNames() (map[string]struct{}, error) {
+	src := "package " + f.pkg + "\n\n" + f.body.String()
+
+	fileAst, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]struct{})
+	ast.Inspect(fileAst, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+			used[id.Name] = struct{}{}
+		}
+
+		return true
+	})
+
+	return used, nil
+}
+
+var versionElem = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName guesses the package name of the import path like goimports does
+func importName(path string) string {
+	elems := strings.Split(path, "/")
+
+	name := elems[len(elems)-1]
+	if versionElem.MatchString(name) && len(elems) > 1 {
+		name = elems[len(elems)-2]
+	}
+
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i] // gopkg.in/yaml.v3
+	}
+
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, name)
+
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "pkg" + name
+	}
+
+	return name
+}
+
+// needsAlias reports whether the name can't be omitted in the import spec
+func needsAlias(path, name string) bool {
+	elems := strings.Split(path, "/")
+	last := elems[len(elems)-1]
+
+	if versionElem.MatchString(last) && len(elems) > 1 {
+		last = elems[len(elems)-2] // major version suffix
+	}
+
+	return name != last
+}
+
+func isStdImport(path string) bool {
+	return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".")
+}
+
+// GenerateDefaults appends a block of variables with the values to the generated file,
+// each variable is labeled with the labels of its result, so it can be extracted again;
+// the sources of the values are recorded in the manifest of the file, see CheckUpToDate
+func GenerateDefaults(f *GenFile, results []Result) error {
+	f.addSources(results)
+
+	f.Printf("var (\n")
+
+	for i, r := range results {
+		lit, typ, err := f.defaultLiteral(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+
+		if i > 0 {
+			f.Printf("\n")
+		}
+
+		labels := r.Labels
+		if len(labels) == 0 && r.Doc != "" {
+			labels = []string{r.Doc}
+		}
+
+		for j, l := range labels {
+			if j == 0 {
+				if l, err = labelLine(l, r.Args); err != nil {
+					return fmt.Errorf("%s: %w", r.Name, err)
+				}
+			}
+			f.Printf("\t// %s\n", l)
+		}
+
+		if typ != "" {
+			f.Printf("\t%s %s = %s\n", r.Name, typ, lit)
+		} else {
+			f.Printf("\t%s = %s\n", r.Name, lit)
+		}
+	}
+
+	f.Printf(")\n")
+
+	return nil
+}
+
+// defaultLiteral returns the source of the result value and the type to declare the variable with if it's needed;
+// types of results are kept: basic values are converted to predeclared types, e.g. `float32(3.14)`,
+// variables of other types are declared with them, e.g. `timeout time.Duration = 30`
+func (f *GenFile) defaultLiteral(r Result) (lit string, typ string, err error) {
+	if err = f.checkImports(r.Type); err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case r.Kind == KindSelectorRef || r.Kind == KindAlloc:
+		lit = fmt.Sprint(r.Value)
+		if r.Kind == KindSelectorRef {
+			err = f.checkImports(lit)
+		}
+		return lit, r.Type, err
+	case isEmptyComposite(r.Value):
+		// the element type is unknown without the declared type
+		if r.Type == "" {
+			return "", "", errors.New("empty value without a declared type")
+		}
+		return r.Type + "{}", r.Type, nil
+	}
+
+	if lit, typ, err = goLiteral(r.Value); err != nil {
+		return "", "", err
+	}
+
+	if r.Format != nil {
+		lit = formatIntLit(r.Value, *r.Format, lit)
+	}
+
+	if r.Type == "" {
+		// the value is untyped in the source
+		return lit, "", nil
+	}
+
+	if r.Kind != KindBasic {
+		// the type of the literal is replaced with the declared one, e.g. []Port{1, 2}
+		return r.Type + lit[strings.IndexByte(lit, '{'):], r.Type, nil
+	}
+
+	if _, ok := basicTypes[r.Type]; ok && r.Type != literalType(r.Value) {
+		return r.Type + "(" + lit + ")", "", nil
+	}
+
+	return lit, r.Type, nil
+}
+
+// literalType returns the default type of the literal of a basic value, e.g. float64 of 3.14
+func literalType(value any) string {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Float32, reflect.Float64:
+		return "float64"
+	}
+	return "int"
+}
+
+// isEmptyComposite reports whether the value is an empty map or slice of maps, which types can't be told by values
+func isEmptyComposite(value any) bool {
+	switch v := value.(type) {
+	case []Entry:
+		return len(v) == 0
+	case [][]Entry:
+		return len(v) == 0
+	}
+	return false
+}
+
+// checkImports returns an error if packages the expression (a type or a qualified identifier) refers to
+// are neither imported with Import nor standard library packages imported automatically
+func (f *GenFile) checkImports(expr string) error {
+	if expr == "" {
+		return nil
+	}
+
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return fmt.Errorf("%q: %w", expr, err)
+	}
+
+	ast.Inspect(e, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		if _, std := stdImports[pkg.Name]; !std && !f.nameTaken(pkg.Name) {
+			err = fmt.Errorf("package %s of %s isn't imported, import it with GenFile.Import", pkg.Name, expr)
+		}
+
+		return false
+	})
+
+	return err
+}
+
+// labelLine returns the label comment text with the arguments written the way splitLabelArgs parses them:
+// trailing key=value pairs, or a parenthesized list of quoted values if some value isn't a single word
+//
+//	parser owner=team-a
+//	parser(note="a b", owner="team-a")
+func labelLine(label string, args map[string]string) (string, error) {
+	if len(args) == 0 {
+		return label, nil
+	}
+
+	keys := sortedKeys(args)
+	plain := true
+
+	for _, k := range keys {
+		if k == "" || !isLabelWord(k) {
+			return "", fmt.Errorf("label argument %q can't be written", k)
+		}
+		plain = plain && isLabelWord(args[k])
+	}
+
+	if plain {
+		for _, k := range keys {
+			label += " " + k + "=" + args[k]
+		}
+		return label, nil
+	}
+
+	if !isLabelWord(label) {
+		return "", fmt.Errorf("arguments of label %q can't be written, it isn't a single word", label)
+	}
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + strconv.Quote(args[k])
+	}
+
+	return label + "(" + strings.Join(pairs, ", ") + ")", nil
+}
+
+// isLabelWord reports whether the text has no characters separating label arguments
+func isLabelWord(s string) bool {
+	return !strings.ContainsFunc(s, unicode.IsSpace) && !strings.ContainsAny(s, "=,()\"'`")
+}
+
+// goLiteral returns Go source of the value and its type if it differs from the default type of the literal
+func goLiteral(value any) (lit string, typ string, err error) {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.String:
+		lit = strconv.Quote(v.String())
+		return lit, defaultType(v.Type(), "string"), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), defaultType(v.Type(), "bool"), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), defaultType(v.Type(), "int"), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), v.Type().String(), nil
+	case reflect.Float32:
+		lit, err = formatFloat(v.Float(), 32)
+		return lit, "float32", err
+	case reflect.Float64:
+		lit, err = formatFloat(v.Float(), 64)
+		return lit, defaultType(v.Type(), "float64"), err
+	case reflect.Slice:
+		if entries, ok := value.([]Entry); ok {
+			return mapLiteral(entries)
+		}
+
+		if maps, ok := value.([][]Entry); ok {
+			return sliceMapLiteral(maps)
+		}
+
+		elems := make([]string, v.Len())
+		for i := range elems {
+			if elems[i], _, err = goLiteral(v.Index(i).Interface()); err != nil {
+				return "", "", err
+			}
+		}
+
+		return v.Type().String() + "{" + strings.Join(elems, ", ") + "}", "", nil
+	}
+
+	return "", "", fmt.Errorf("unsupported value type %T", value)
+}
+
+// formatIntLit formats an integer value with the format, returning the default literal for other values
+func formatIntLit(value any, format NumFormat, def string) string {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return format.FormatInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return format.FormatUint(v.Uint())
+	}
+
+	return def
+}
+
+func sliceMapLiteral(maps [][]Entry) (lit string, typ string, err error) {
+	if len(maps) == 0 {
+		return "", "", errors.New("empty slice of maps")
+	}
+
+	elems := make([]string, len(maps))
+	mapType := ""
+
+	for i, entries := range maps {
+		if elems[i], _, err = mapLiteral(entries); err != nil {
+			return "", "", err
+		}
+
+		// the element type is elided in the slice literal
+		mapType = elems[i][:strings.Index(elems[i], "{")]
+		elems[i] = elems[i][len(mapType):]
+	}
+
+	return "[]" + mapType + "{" + strings.Join(elems, ", ") + "}", "", nil
+}
+
+func mapLiteral(entries []Entry) (lit string, typ string, err error) {
+	if len(entries) == 0 {
+		return "", "", errors.New("empty map")
+	}
+
+	mapType := reflect.MapOf(reflect.TypeOf(entries[0].Key), reflect.TypeOf(entries[0].Value)).String()
+
+	elems := make([]string, len(entries))
+	for i, e := range entries {
+		k, _, err := goLiteral(e.Key)
+		if err != nil {
+			return "", "", err
+		}
+
+		v, _, err := goLiteral(e.Value)
+		if err != nil {
+			return "", "", err
+		}
+
+		if e.Format != nil {
+			v = formatIntLit(e.Value, *e.Format, v)
+		}
+
+		elems[i] = k + ": " + v
+	}
+
+	return mapType + "{" + strings.Join(elems, ", ") + "}", "", nil
+}
+
+func defaultType(t reflect.Type, def string) string {
+	if t.String() == def {
+		return ""
+	}
+	return t.String()
+}
@@ -0,0 +1,163 @@
+package goparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLabelLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		args  map[string]string
+		want  string
+	}{
+		{name: "no args", label: "cfg", want: "cfg"},
+		{name: "plain", label: "cfg", args: map[string]string{"owner": "team-a", "env": "prod"}, want: "cfg env=prod owner=team-a"},
+		{name: "spaces", label: "cfg", args: map[string]string{"note": "a b", "owner": "x"}, want: `cfg(note="a b", owner="x")`},
+		{name: "quotes", label: "cfg", args: map[string]string{"note": `say "hi", (ok)`}, want: `cfg(note="say \"hi\", (ok)")`},
+		{name: "empty value", label: "cfg", args: map[string]string{"note": ""}, want: "cfg note="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := labelLine(tt.label, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+
+			label, args := splitLabelArgs(got)
+			if label != tt.label {
+				t.Errorf("parsed label %q, want %q", label, tt.label)
+			}
+			if len(tt.args) > 0 && !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("parsed args %v, want %v", args, tt.args)
+			}
+		})
+	}
+
+	if _, err := labelLine("my cfg", map[string]string{"note": "a b"}); err == nil {
+		t.Error("no error for quoted args of a label with spaces")
+	}
+	if _, err := labelLine("cfg", map[string]string{"a b": "x"}); err == nil {
+		t.Error("no error for a key with spaces")
+	}
+}
+
+func TestGenerateDefaults(t *testing.T) {
+	p := newTestParser(t, `package p
+
+import "time"
+
+type MyString string
+
+// cfg(note="two words")
+var f = float32(3.14)
+
+// cfg
+var s = MyString("x")
+
+// cfg
+var n = int64(7)
+
+// cfg
+var d time.Duration = 30
+
+// cfg
+var ports = []int{80, 443}
+`)
+
+	results := CollectValues(p, "cfg").Results
+	if len(results) != 5 {
+		t.Fatalf("got %d results, want 5: %+v", len(results), results)
+	}
+
+	results = append(results,
+		Result{Kind: KindSliceMap, Name: "empty", Doc: "cfg", Value: [][]Entry{}, Type: "[]map[string]int"},
+		Result{Kind: KindMap, Name: "none", Doc: "cfg", Value: []Entry{}, Type: "map[string]int"},
+	)
+
+	f := NewGenFile("p")
+	if err := GenerateDefaults(f, results); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`// cfg(note="two words")`,
+		`f = float32(3.14)`,
+		`s MyString = "x"`,
+		`n = int64(7)`,
+		`d time.Duration = 30`,
+		`ports = []int64{80, 443}`,
+		`empty []map[string]int = []map[string]int{}`,
+		`none map[string]int = map[string]int{}`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("no %s in\n%s", want, src)
+		}
+	}
+
+	mismatches, err := CheckRoundTrip(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mismatches) > 0 {
+		t.Errorf("got mismatches %+v", mismatches)
+	}
+}
+
+func TestGenerateDefaultsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+	}{
+		{name: "empty slice of maps", result: Result{Kind: KindBasic, Name: "x", Value: [][]Entry{}}},
+		{name: "empty map", result: Result{Kind: KindBasic, Name: "x", Value: []Entry{}}},
+		{name: "selector", result: Result{Kind: KindSelectorRef, Name: "x", Value: "zap.InfoLevel"}},
+		{name: "type", result: Result{Kind: KindBasic, Name: "x", Value: 1, Type: "zapcore.Level"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := GenerateDefaults(NewGenFile("p"), []Result{tt.result}); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+
+	f := NewGenFile("p")
+	f.Import("go.uber.org/zap")
+
+	if err := GenerateDefaults(f, []Result{{Kind: KindSelectorRef, Name: "x", Value: "zap.InfoLevel"}}); err != nil {
+		t.Errorf("imported selector: %v", err)
+	}
+}
+
+func TestCheckRoundTripTypes(t *testing.T) {
+	results := []Result{
+		{Kind: KindBasic, Name: "a", Doc: "cfg", Value: int64(1), Type: "int64"},
+		{Kind: KindBasic, Name: "b", Doc: "cfg", Value: 1.5, Type: "float64"},
+		{Kind: KindBasic, Name: "c", Doc: "cfg", Value: int64(1)},
+		{Kind: KindSlice, Name: "d", Doc: "cfg", Value: []int64{1, 2}, Type: "Ports"},
+	}
+
+	mismatches, err := CheckRoundTrip(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mismatches) > 0 {
+		t.Errorf("got mismatches %+v", mismatches)
+	}
+}
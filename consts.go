@@ -0,0 +1,108 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// UntypedConst is the key of untyped constants in results of GetConstsByType
+const UntypedConst = "untyped"
+
+// GetConstsByType returns all exported package-level constants regardless of labels, grouped by their declared type
+// written as in the source, e.g. "time.Duration"; untyped constants are grouped by UntypedConst.
+// In typed mode constants without a declared type are grouped by their type, e.g. `3 * time.Second` by "time.Duration".
+// Values are evaluated like labeled values (iota and constant expressions included) and typed like by InferValues
+// (see WithDeclaredTypes);
+// constants whose values can't be evaluated are omitted
+//
+//	type Color int
+//
+//	const (
+//	    Red Color = iota // consts["Color"]
+//	    Green            // consts["Color"]
+//	)
+//
+//	const MaxSize = 1 << 20 // consts[UntypedConst]
+func GetConstsByType(g *GoParser) map[string][]AnyValue {
+	result := make(map[string][]AnyValue)
+	toAny := anyValue(g)
+
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.GenDecl)
+			if !ok || decl.Tok != token.CONST {
+				continue
+			}
+
+			var prevValues []ast.Expr
+			var typ ast.Expr
+
+			for i, spec := range decl.Specs {
+				s := spec.(*ast.ValueSpec)
+
+				values := s.Values
+				if len(values) == 0 {
+					values = prevValues // implicit repetition of the previous expression list
+				} else {
+					typ = s.Type
+				}
+				prevValues = values
+
+				key := UntypedConst
+				if typ != nil {
+					key = types.ExprString(typ)
+				}
+
+				if len(values) != len(s.Names) {
+					continue
+				}
+
+				for j, n := range s.Names {
+					if !n.IsExported() {
+						continue
+					}
+
+					key := key
+					if typ == nil {
+						key = g.constType(n)
+					}
+
+					val := f.resolve(values[j], g.opts.resolveDepth)
+
+					info := DeclInfo{
+						Name:     n.Name,
+						Type:     exprString(typ),
+						Package:  f.pkgPath,
+						Pos:      f.fset.Position(n.Pos()),
+						Checksum: f.checksum(s.Doc.Text(), n.Name, val),
+					}
+
+					if v := toAny(f, info, floatLit(typ, g.constLit(f, n, val, i))); v != nil {
+						result[key] = append(result[key], *v)
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// constType returns the type of a constant in typed mode qualified by package names, UntypedConst for untyped ones
+func (g *GoParser) constType(n *ast.Ident) string {
+	if g.types == nil {
+		return UntypedConst
+	}
+
+	c, ok := g.types.info.Defs[n].(*types.Const)
+	if !ok {
+		return UntypedConst
+	}
+
+	if b, ok := c.Type().(*types.Basic); ok && b.Info()&types.IsUntyped != 0 {
+		return UntypedConst
+	}
+
+	return g.objectType(n)
+}
@@ -0,0 +1,206 @@
+package goparser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DecodeStruct decodes struct literals with the label (see GetStructValues) into values of the struct type T,
+// like json.Unmarshal decodes JSON objects: fields are matched by name (exactly or case-insensitively),
+// fields missing in T are ignored, nested struct literals, slices and maps are decoded recursively;
+// integers and floats are converted to numeric fields if they fit, time units and constant expressions
+// of them like time.Second or `30 * time.Second` are decoded into time.Duration fields
+//
+//	type Config struct {
+//	    Host string
+//	    Port int
+//	}
+//
+//	// parser:defaults
+//	var defaults = Config{Host: "localhost", Port: 8080}
+//
+//	configs, err := DecodeStruct[Config](p, "parser:defaults") // []Config{{Host: "localhost", Port: 8080}}
+func DecodeStruct[T any](g *GoParser, label string) ([]T, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't decode struct literals into %s, a struct type is expected", t)
+	}
+
+	values := GetStructValues(g, label)
+	result := make([]T, len(values))
+
+	for i, v := range values {
+		if err := decodeValue(reflect.ValueOf(&result[i]).Elem(), v.Value, v.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// decodeValue sets the value of a struct value (see StructValue) to dst, path names the value in errors
+func decodeValue(dst reflect.Value, v any, path string) error {
+	if dst.Kind() == reflect.Pointer {
+		dst.Set(reflect.New(dst.Type().Elem()))
+		return decodeValue(dst.Elem(), v, path)
+	}
+
+	if ref, ok := v.(SelectorRef); ok && dst.Type() == durationType {
+		_, unit, _ := strings.Cut(string(ref), ".")
+		if n, ok := durationUnits[unit]; ok {
+			dst.SetInt(n)
+			return nil
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		if dst.Kind() != reflect.Struct {
+			break
+		}
+
+		for _, name := range sortedKeys(val) {
+			fv := val[name]
+			field := dst.FieldByNameFunc(func(s string) bool {
+				return s == name
+			})
+			if !field.IsValid() {
+				field = dst.FieldByNameFunc(func(s string) bool {
+					return strings.EqualFold(s, name)
+				})
+			}
+
+			if !field.IsValid() || !field.CanSet() {
+				continue
+			}
+
+			if err := decodeValue(field, fv, path+"."+name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case []any:
+		switch dst.Kind() {
+		case reflect.Slice:
+			dst.Set(reflect.MakeSlice(dst.Type(), len(val), len(val)))
+		case reflect.Array:
+			if len(val) > dst.Len() {
+				return fmt.Errorf("%s: %d elements don't fit into %s", path, len(val), dst.Type())
+			}
+		default:
+			return fmt.Errorf("%s: can't decode a slice into %s", path, dst.Type())
+		}
+
+		for i, ev := range val {
+			if err := decodeValue(dst.Index(i), ev, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case map[any]any:
+		if dst.Kind() != reflect.Map {
+			break
+		}
+
+		dst.Set(reflect.MakeMapWithSize(dst.Type(), len(val)))
+
+		for k, ev := range val {
+			key := reflect.New(dst.Type().Key()).Elem()
+			if err := decodeValue(key, k, fmt.Sprintf("%s[%v]", path, k)); err != nil {
+				return err
+			}
+
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := decodeValue(elem, ev, fmt.Sprintf("%s[%v]", path, k)); err != nil {
+				return err
+			}
+
+			dst.SetMapIndex(key, elem)
+		}
+
+		return nil
+	default:
+		if dst.Kind() == reflect.Interface && dst.NumMethod() == 0 {
+			dst.Set(reflect.ValueOf(v))
+			return nil
+		}
+
+		if decodeBasic(dst, v) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: can't decode %T into %s", path, v, dst.Type())
+}
+
+// decodeBasic sets a basic value to dst of a compatible kind, numbers are set if they fit
+func decodeBasic(dst reflect.Value, v any) bool {
+	rv := reflect.ValueOf(v)
+
+	switch dst.Kind() {
+	case reflect.String:
+		if rv.Kind() != reflect.String {
+			return false
+		}
+		dst.SetString(rv.String())
+	case reflect.Bool:
+		if rv.Kind() != reflect.Bool {
+			return false
+		}
+		dst.SetBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch {
+		case rv.CanInt():
+			n = rv.Int()
+		case rv.CanUint() && rv.Uint() <= 1<<63-1:
+			n = int64(rv.Uint())
+		default:
+			return false
+		}
+		if dst.OverflowInt(n) {
+			return false
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		switch {
+		case rv.CanUint():
+			n = rv.Uint()
+		case rv.CanInt() && rv.Int() >= 0:
+			n = uint64(rv.Int())
+		default:
+			return false
+		}
+		if dst.OverflowUint(n) {
+			return false
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch {
+		case rv.CanFloat():
+			f = rv.Float()
+		case rv.CanInt():
+			f = float64(rv.Int())
+		case rv.CanUint():
+			f = float64(rv.Uint())
+		default:
+			return false
+		}
+		if dst.OverflowFloat(f) {
+			return false
+		}
+		dst.SetFloat(f)
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,62 @@
+package goparser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeStruct(t *testing.T) {
+	p := newTestParser(t, `package p
+
+import "time"
+
+type Config struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+	Retry   time.Duration
+	Backoff time.Duration
+	Tags    []string
+}
+
+const retries = 3
+
+// parser:defaults
+var defaults = Config{
+	Host:    "localhost",
+	Port:    8080,
+	Timeout: 30 * time.Second,
+	Retry:   time.Minute,
+	Backoff: retries * (time.Second + 500*time.Millisecond),
+	Tags:    []string{"a", "b"},
+}
+`)
+
+	type config struct {
+		Host    string
+		Port    int
+		Timeout time.Duration
+		Retry   time.Duration
+		Backoff time.Duration
+		Tags    []string
+	}
+
+	got, err := DecodeStruct[config](p, "parser:defaults")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []config{{
+		Host:    "localhost",
+		Port:    8080,
+		Timeout: 30 * time.Second,
+		Retry:   time.Minute,
+		Backoff: 3 * (time.Second + 500*time.Millisecond),
+		Tags:    []string{"a", "b"},
+	}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Diagnostic kinds
+const (
+	DiagNoValue     = "noValue"     // a variable without an initializer, e.g. `var a int`
+	DiagMultiValue  = "multiValue"  // a name initialized with a multi-value expression, e.g. `var a, b = f()`
+	DiagEmpty       = "empty"       // a value declared without literal content, e.g. `make(map[string]int)`, see GetAllocValues
+	DiagUnsupported = "unsupported" // a value that isn't a literal, e.g. a function call
+
+	// DiagDocTruncated is a declaration without matched labels whose doc comment isn't scanned completely
+	// because of limits set with WithMaxLabelLength or WithMaxDocLines
+	DiagDocTruncated = "docTruncated"
+)
+
+// Diagnostic contains a labeled declaration without an extractable literal value and the reason
+type Diagnostic struct {
+	Name string
+	Pos  token.Position
+
+	// Kind is one of DiagNoValue, DiagMultiValue, DiagEmpty, DiagUnsupported and DiagDocTruncated
+	Kind string
+
+	// Message is the reason, e.g. "no value" for `var a int`
+	Message string
+}
+
+// Diagnose returns labeled declarations without an extractable literal value: ones skipped by extraction
+// because they have no value of their own, e.g. variables without an initializer or ones initialized
+// with a multi-value function call, values declared empty with make or new, and values that aren't literals;
+// declarations whose doc comments aren't scanned completely because of limits are reported too:
+//
+//	// someLabel
+//	var a, b = f() // a and b are skipped
+//
+//	// someLabel
+//	var m = make(map[string]int, 8) // declared, but empty
+func Diagnose(g *GoParser, docLabels ...string) []Diagnostic {
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	result := make([]Diagnostic, 0)
+
+	add := func(info DeclInfo, kind, reason string) {
+		result = append(result, Diagnostic{Name: info.Name, Pos: info.Pos, Kind: kind, Message: reason})
+	}
+
+	g.walkLabeled(docMap, func(f *file, info DeclInfo, val ast.Expr) {
+		if _, call, ok := allocCall(val); ok {
+			add(info, DiagEmpty, "allocated with "+string(f.source(call)))
+			return
+		}
+
+		if _, ok := val.(*ast.CompositeLit); ok {
+			return
+		}
+
+		if _, ok := f.inferValue(val); !ok {
+			add(info, DiagUnsupported, "unsupported value "+string(f.source(val)))
+		}
+	}, add)
+
+	return result
+}
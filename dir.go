@@ -0,0 +1,330 @@
+package goparser
+
+import (
+	"fmt"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// NewFromDir returns a new instance of GoParser containing all Go files of the directory and its subdirectories;
+// like the go tool, it skips testdata and vendor directories and files and directories beginning with "." or "_"
+func NewFromDir(dir string, opts ...Option) (*GoParser, error) {
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !stat.IsDir() {
+		return nil, fmt.Errorf("%q is not a directory", dir)
+	}
+
+	return newFromDir(dir, true, newOptions(opts))
+}
+
+// NewPackagesFromDir returns instances of GoParser for each package of the directory (not including subdirectories)
+// by package name, e.g. when it contains both foo and foo_test packages; see NewFromDir for skipped files
+//
+//	pkgs, err := NewPackagesFromDir("./config")
+//	...
+//	values := GetBasicValues[string](pkgs["config"], "default")
+func NewPackagesFromDir(dir string, opts ...Option) (map[string]*GoParser, error) {
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !stat.IsDir() {
+		return nil, fmt.Errorf("%q is not a directory", dir)
+	}
+
+	g, err := newFromDir(dir, false, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs := make(map[string]*GoParser)
+	for _, f := range g.files {
+		name := f.ast.Name.Name
+
+		p, ok := pkgs[name]
+		if !ok {
+			// type information is keyed by nodes, so it can be shared
+			p = &GoParser{opts: g.opts, types: g.types}
+			pkgs[name] = p
+		}
+
+		p.files = append(p.files, f)
+	}
+
+	return pkgs, nil
+}
+
+// NewFromPattern returns a new instance of GoParser containing Go files matching the package pattern of the go tool:
+// "./..." is the current directory and its subdirectories, "./internal/..." is the internal directory and its
+// subdirectories, a pattern without "..." is a single directory; see NewFromDir for skipped files and directories
+//
+//	p, err := NewFromPattern("./...")
+func NewFromPattern(pattern string, opts ...Option) (*GoParser, error) {
+	dir, recursive := pattern, false
+	if pattern == "..." || strings.HasSuffix(pattern, "/...") {
+		dir, recursive = strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/"), true
+	}
+
+	if dir == "" {
+		dir = "."
+	}
+
+	if strings.Contains(dir, "...") {
+		return nil, fmt.Errorf("unsupported pattern %q: \"...\" is only supported as the last path element", pattern)
+	}
+
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !stat.IsDir() {
+		return nil, fmt.Errorf("%q is not a directory", dir)
+	}
+
+	return newFromDir(dir, recursive, newOptions(opts))
+}
+
+func newFromDir(dir string, recursive bool, o *options) (*GoParser, error) {
+	fset := token.NewFileSet()
+	g := &GoParser{opts: o}
+
+	parsed := make(map[string]struct{})
+
+	add := func(p string) error {
+		src, err := o.readFile(p)
+		if err != nil {
+			return err
+		}
+
+		f, err := parseFile(fset, p, src, o)
+		if err != nil {
+			return err
+		}
+
+		g.files = append(g.files, f)
+		parsed[overlayKey(p)] = struct{}{}
+
+		return nil
+	}
+
+	if err := o.walkDir(dir, recursive, add); err != nil {
+		return nil, err
+	}
+
+	for _, p := range o.overlayFiles(dir, recursive) {
+		if _, ok := parsed[overlayKey(p)]; ok {
+			continue
+		}
+
+		if err := add(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(g.files) == 0 {
+		return nil, fmt.Errorf("no Go files in %q", dir)
+	}
+
+	if o.types {
+		g.checkTypes()
+	}
+
+	g.setPackagePaths()
+
+	return g, nil
+}
+
+// walkDir calls fn for each Go file of the directory (and its subdirectories if recursive) that passes the filters
+func (o *options) walkDir(root string, recursive bool, fn func(path string) error) error {
+	visited := make(map[string]struct{})
+
+	var walk func(dir, rel string) error
+	walk = func(dir, rel string) error {
+		if o.followSymlinks {
+			real, err := filepath.EvalSymlinks(dir)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := visited[real]; ok {
+				return nil // symlink cycle
+			}
+			visited[real] = struct{}{}
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			name := e.Name()
+			p := filepath.Join(dir, name)
+			r := path.Join(rel, name)
+
+			isDir := e.IsDir()
+			if e.Type()&fs.ModeSymlink != 0 {
+				stat, err := os.Stat(p)
+				if err != nil {
+					continue // broken link
+				}
+
+				if stat.IsDir() {
+					if !o.followSymlinks {
+						continue
+					}
+					isDir = true
+				}
+			}
+
+			if isDir {
+				if !recursive || skipDir(name) || o.excludeDir(r) || (!o.nestedModules && isModuleRoot(p)) {
+					continue
+				}
+
+				if err = walk(p, r); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if !isGoFile(name) || !o.matchFile(r) {
+				continue
+			}
+
+			if err = fn(p); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return walk(root, "")
+}
+
+// overlayFiles returns sorted paths of overlay files in the directory (or its subdirectories if recursive)
+// that would be walked if they existed on disk
+func (o *options) overlayFiles(dir string, recursive bool) []string {
+	root := overlayKey(dir)
+	files := make([]string, 0)
+
+	for _, p := range sortedKeys(o.overlay) {
+		rel, err := filepath.Rel(root, p)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if !recursive && len(parts) > 1 {
+			continue
+		}
+
+		skip := false
+		for _, d := range parts[:len(parts)-1] {
+			skip = skip || skipDir(d)
+		}
+
+		r := filepath.ToSlash(rel)
+		if skip || o.excludeDir(path.Dir(r)) || !isGoFile(path.Base(r)) || !o.matchFile(r) {
+			continue
+		}
+
+		files = append(files, filepath.Join(dir, rel))
+	}
+
+	return files
+}
+
+func isModuleRoot(dir string) bool {
+	stat, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil && !stat.IsDir()
+}
+
+func skipDir(name string) bool {
+	return name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func isGoFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasPrefix(name, ".") && !strings.HasPrefix(name, "_")
+}
+
+// matchFile reports whether the file passes include and exclude filters
+func (o *options) matchFile(rel string) bool {
+	if len(o.include) > 0 && !matchAny(o.include, rel) {
+		return false
+	}
+
+	return !matchAny(o.exclude, rel)
+}
+
+// excludeDir reports whether all files of the directory are excluded by a pattern like "**/mocks/**",
+// so it isn't walked at all
+func (o *options) excludeDir(rel string) bool {
+	for _, p := range o.exclude {
+		if prefix := strings.TrimSuffix(p, "/**"); prefix != p && matchGlob(prefix, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny reports whether the name matches any of the patterns, patterns without "/" are matched against the base name
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if !strings.Contains(p, "/") {
+			if ok, _ := path.Match(p, path.Base(name)); ok {
+				return true
+			}
+			continue
+		}
+
+		if matchGlob(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether the slash-separated name matches the pattern;
+// the pattern syntax is the same as for path.Match, plus `**` matching any number of path elements
+func matchGlob(pattern, name string) bool {
+	return matchParts(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchParts(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchParts(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
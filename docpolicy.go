@@ -0,0 +1,120 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// DocPolicy selects comments labels are matched in, depending on where gofmt-formatted code attaches them
+type DocPolicy uint
+
+const (
+	// DocSpec is the doc comment of a value spec:
+	//
+	//	var (
+	//		// label
+	//		x = 1
+	//	)
+	DocSpec DocPolicy = 1 << iota
+
+	// DocDecl is the doc comment of a declaration with a single spec, ungrouped or parenthesized,
+	// or of a const block with implicitly repeated specs, e.g. an iota-based enum, labeling all of its constants:
+	//
+	//	// label
+	//	var x = 1
+	//
+	//	// label
+	//	var (
+	//		x = 1
+	//	)
+	//
+	//	// label
+	//	const (
+	//		Red Color = iota
+	//		Green
+	//	)
+	DocDecl
+
+	// DocLine is the line comment following a value spec:
+	//
+	//	var x = 1 // label
+	DocLine
+
+	// DocRun makes labels of a spec doc comment apply to the following specs without doc comments
+	// up to a blank line; it's opt-in:
+	//
+	//	var (
+	//		// label
+	//		a = 1
+	//		b = 2 // labeled too
+	//
+	//		c = 3 // not labeled
+	//	)
+	DocRun
+
+	// DefaultDocPolicy matches labels in spec and single-spec declaration doc comments
+	DefaultDocPolicy = DocSpec | DocDecl
+)
+
+// WithDocPolicy sets comments labels are matched in, DefaultDocPolicy by default;
+// when several comments are selected, labels of the spec doc come first, then the declaration doc and the line comment
+func WithDocPolicy(p DocPolicy) Option {
+	return func(o *options) {
+		o.docPolicy = p
+	}
+}
+
+// labelComments returns comment groups of the spec selected by the doc policy
+func (o *options) labelComments(decl *ast.GenDecl, spec *ast.ValueSpec) []*ast.CommentGroup {
+	var groups []*ast.CommentGroup
+
+	if o.docPolicy&DocSpec != 0 && spec.Doc != nil {
+		groups = append(groups, spec.Doc)
+	}
+
+	if o.docPolicy&DocDecl != 0 && decl.Doc != nil && (len(decl.Specs) == 1 || isEnumBlock(decl)) {
+		groups = append(groups, decl.Doc)
+	}
+
+	if o.docPolicy&DocLine != 0 && spec.Comment != nil {
+		groups = append(groups, spec.Comment)
+	}
+
+	return groups
+}
+
+// isEnumBlock reports whether the declaration is a const block with implicitly repeated specs, e.g. an iota-based enum
+func isEnumBlock(decl *ast.GenDecl) bool {
+	if decl.Tok != token.CONST || len(decl.Specs) < 2 {
+		return false
+	}
+
+	for _, spec := range decl.Specs[1:] {
+		if s, ok := spec.(*ast.ValueSpec); ok && len(s.Values) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// descriptionText returns the text of doc comment lines that aren't labels, without comment markers and directives
+func descriptionText(prose []*ast.Comment) string {
+	if len(prose) == 0 {
+		return ""
+	}
+
+	group := ast.CommentGroup{List: prose}
+
+	return strings.TrimSpace(group.Text())
+}
+
+// commentsText returns the joined text of the comment groups
+func commentsText(groups []*ast.CommentGroup) string {
+	var sb strings.Builder
+	for _, g := range groups {
+		sb.WriteString(g.Text())
+	}
+	return sb.String()
+}
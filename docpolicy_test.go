@@ -0,0 +1,122 @@
+package goparser
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// docLayouts contains comment attachment layouts gofmt produces, values of labeled specs start with 1
+const docLayouts = `package p
+
+var (
+	// lbl
+	specDoc = 1
+)
+
+// lbl
+var declDoc = 1
+
+// lbl
+var (
+	groupDoc = 1
+)
+
+// lbl
+var (
+	multiA = 0
+	multiB = 0
+)
+
+var lineComment = 1 // lbl
+
+var (
+	// lbl
+	runA = 1
+	runB = 1 // not labeled by itself
+
+	runC = 0
+)
+
+// prose before the label
+// lbl
+// prose after the label
+var prose = 1
+
+var inline = ( /* lbl */ 0)
+
+var (
+	/* lbl */
+	blockDoc = 1
+)
+`
+
+func TestDocPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy DocPolicy
+		want   []string
+	}{
+		{
+			name:   "default",
+			policy: DefaultDocPolicy,
+			want:   []string{"blockDoc", "declDoc", "groupDoc", "prose", "runA", "specDoc"},
+		},
+		{
+			name:   "spec",
+			policy: DocSpec,
+			want:   []string{"blockDoc", "runA", "specDoc"},
+		},
+		{
+			name:   "decl",
+			policy: DocDecl,
+			want:   []string{"declDoc", "groupDoc", "prose"},
+		},
+		{
+			name:   "line",
+			policy: DefaultDocPolicy | DocLine,
+			want:   []string{"blockDoc", "declDoc", "groupDoc", "lineComment", "prose", "runA", "specDoc"},
+		},
+		{
+			name:   "run",
+			policy: DefaultDocPolicy | DocRun,
+			want:   []string{"blockDoc", "declDoc", "groupDoc", "prose", "runA", "runB", "specDoc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParser(t, docLayouts, WithDocPolicy(tt.policy))
+
+			got := make([]string, 0)
+			for _, v := range GetBasicValues[int](p, "lbl") {
+				if v.Value != 1 {
+					t.Errorf("%s is labeled", v.Name)
+				}
+				got = append(got, v.Name)
+			}
+			slices.Sort(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescription(t *testing.T) {
+	p := newTestParser(t, docLayouts)
+
+	for _, v := range GetBasicValues[int](p, "lbl") {
+		if v.Name != "prose" {
+			continue
+		}
+
+		if v.Label != "lbl" || v.Description != "prose before the label\nprose after the label" {
+			t.Errorf("got label %q, description %q", v.Label, v.Description)
+		}
+		return
+	}
+
+	t.Error("prose isn't extracted")
+}
@@ -0,0 +1,215 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationValue contains a time.Duration value written as an expression of time units
+type DurationValue struct {
+	DeclInfo
+	Value time.Duration
+}
+
+// GetDurationValues returns a list of time.Duration values by godoc label; values are constant expressions
+// of literals and time units, e.g. `30 * time.Second` or `time.Hour + 30*time.Minute`, or literals of variables
+// declared as time.Duration; in typed mode any constant of the time.Duration type is returned
+//
+//	// someLabel
+//	var timeout = 30 * time.Second // 30s
+func GetDurationValues(g *GoParser, docLabels ...string) []DurationValue {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return appendDeclsFunc(newResults[DurationValue](g), g, docMap, func(dst []DurationValue, f *file, info DeclInfo, val ast.Expr) []DurationValue {
+		timePkg := f.timePkgName()
+		if timePkg == "" && g.types == nil {
+			return dst
+		}
+
+		v, unit, ok := f.evalDuration(val, timePkg, g.opts.resolveDepth)
+		if !ok || !unit && info.Type != timePkg+".Duration" && !g.isDurationDecl(f, info) {
+			return dst
+		}
+
+		d, ok := constant.Int64Val(constant.ToInt(v))
+		if !ok {
+			return dst
+		}
+
+		return append(dst, DurationValue{DeclInfo: info, Value: time.Duration(d)})
+	})
+}
+
+// durationValue returns the value of a constant expression of time units, e.g. `30 * time.Second`
+func (f *file) durationValue(expr ast.Expr, depth int) (any, bool) {
+	timePkg := f.timePkgName()
+	if timePkg == "" {
+		return nil, false
+	}
+
+	v, unit, ok := f.evalDuration(expr, timePkg, depth)
+	if !ok || !unit {
+		return nil, false
+	}
+
+	d, ok := constant.Int64Val(constant.ToInt(v))
+	if !ok {
+		return nil, false
+	}
+
+	return time.Duration(d), true
+}
+
+// durationUnits contains values of time units in nanoseconds
+var durationUnits = map[string]int64{
+	"Nanosecond":  int64(time.Nanosecond),
+	"Microsecond": int64(time.Microsecond),
+	"Millisecond": int64(time.Millisecond),
+	"Second":      int64(time.Second),
+	"Minute":      int64(time.Minute),
+	"Hour":        int64(time.Hour),
+}
+
+// evalDuration evaluates a constant expression of time units (time.Second), conversions to time.Duration
+// and constant expressions evaluated with evalConst; unit is true if the expression contains a time unit
+func (f *file) evalDuration(expr ast.Expr, timePkg string, depth int) (v constant.Value, unit bool, ok bool) {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		if !f.isPkgSelector(e, timePkg) {
+			return nil, false, false
+		}
+
+		n, ok := durationUnits[e.Sel.Name]
+		if !ok {
+			return nil, false, false
+		}
+
+		return constant.MakeInt64(n), true, true
+	case *ast.ParenExpr:
+		return f.evalDuration(e.X, timePkg, depth)
+	case *ast.Ident:
+		if depth > 0 {
+			if next := f.initExpr(e); next != nil {
+				return f.evalDuration(next, timePkg, depth-1)
+			}
+		}
+	case *ast.CallExpr:
+		sel, isSel := e.Fun.(*ast.SelectorExpr)
+		if isSel && f.isPkgSelector(sel, timePkg) && sel.Sel.Name == "Duration" && len(e.Args) == 1 {
+			x, unit, ok := f.evalDuration(e.Args[0], timePkg, depth)
+			if !ok {
+				return nil, false, false
+			}
+
+			x = constant.ToInt(x)
+			return x, unit, x.Kind() == constant.Int
+		}
+	case *ast.UnaryExpr:
+		x, unit, ok := f.evalDuration(e.X, timePkg, depth)
+		if !ok {
+			return nil, false, false
+		}
+
+		v, ok := unaryOp(e.Op, x)
+		return v, unit, ok
+	case *ast.BinaryExpr:
+		x, xUnit, ok := f.evalDuration(e.X, timePkg, depth)
+		if !ok {
+			return nil, false, false
+		}
+
+		y, yUnit, ok := f.evalDuration(e.Y, timePkg, depth)
+		if !ok {
+			return nil, false, false
+		}
+
+		v, ok := binaryOp(x, e.Op, y)
+		if !ok || !isNumeric(v) {
+			return nil, false, false
+		}
+
+		return v, xUnit || yUnit, true
+	}
+
+	v, ok = f.evalConst(expr, depth, -1)
+	if !ok || !isNumeric(v) {
+		return nil, false, false
+	}
+
+	return v, false, true
+}
+
+// timePkgName returns the name the time package is imported with by the file, empty if it isn't imported
+func (f *file) timePkgName() string {
+	return f.importName("time")
+}
+
+// importName returns the name the package is imported with by the file, empty if it isn't imported
+// or is imported as _ or .
+func (f *file) importName(pkgPath string) string {
+	for _, imp := range f.ast.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err != nil || path != pkgPath {
+			continue
+		}
+
+		if imp.Name == nil {
+			return pkgPath[strings.LastIndexByte(pkgPath, '/')+1:]
+		}
+
+		if imp.Name.Name != "_" && imp.Name.Name != "." {
+			return imp.Name.Name
+		}
+	}
+
+	return ""
+}
+
+// isPkgSelector reports whether the selector is qualified by the package name (not shadowed by a declaration of the file)
+func (f *file) isPkgSelector(sel *ast.SelectorExpr, pkg string) bool {
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Obj == nil && id.Name == pkg
+}
+
+// isDurationDecl reports whether the package-level declaration is of the time.Duration type in typed mode
+func (g *GoParser) isDurationDecl(f *file, info DeclInfo) bool {
+	if g.types == nil || f.ast.Scope == nil || info.Func != "" {
+		return false
+	}
+
+	obj := f.ast.Scope.Lookup(info.Name)
+	if obj == nil {
+		return false
+	}
+
+	spec, ok := obj.Decl.(*ast.ValueSpec)
+	if !ok {
+		return false
+	}
+
+	for _, n := range spec.Names {
+		if n.Name != info.Name {
+			continue
+		}
+
+		def := g.types.info.Defs[n]
+		if def == nil {
+			return false
+		}
+
+		named, ok := def.Type().(*types.Named)
+		return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Duration"
+	}
+
+	return false
+}
@@ -0,0 +1,340 @@
+package goparser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// edit contains a replacement of the source in [start, end)
+type edit struct {
+	start, end int
+	text       string
+}
+
+// SetValue replaces the initializer of a package level variable or constant with the source text of the value;
+// changes are kept in memory until WriteFiles is called
+//
+//	SetValue(p, "timeout", "30 * time.Second")
+func SetValue(g *GoParser, name, value string) error {
+	f, spec, i := findValueSpec(g, name)
+	if spec == nil {
+		return fmt.Errorf("%q not found", name)
+	}
+
+	if i >= len(spec.Values) {
+		return fmt.Errorf("%q has no initializer", name)
+	}
+
+	val := spec.Values[i]
+	g.addEdit(f, f.offset(val.Pos()), f.offset(val.End()), value)
+
+	return nil
+}
+
+// SetBasicValue replaces the initializer of a package level variable or constant with the literal value,
+// see SetValue; integer literals keep the format of the replaced literal (e.g. 0xFF)
+func SetBasicValue[V iLit](g *GoParser, name string, value V) error {
+	var lit string
+	var err error
+	switch v := (interface{})(value).(type) {
+	case string:
+		lit = strconv.Quote(v)
+	case float32:
+		lit, err = formatFloat(float64(v), 32)
+	case float64:
+		lit, err = formatFloat(v, 64)
+	default:
+		lit = fmt.Sprint(v)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%q: %w", name, err)
+	}
+
+	if _, spec, i := findValueSpec(g, name); spec != nil && i < len(spec.Values) {
+		if old, ok := spec.Values[i].(*ast.BasicLit); ok && old.Kind == token.INT {
+			lit = formatIntLit(value, intFormat(old.Value), lit)
+		}
+	}
+
+	return SetValue(g, name, lit)
+}
+
+// formatFloat formats the float as a floating-point literal, keeping the decimal point for integral values;
+// infinities and NaN have no literals
+func formatFloat(v float64, bitSize int) (string, error) {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return "", fmt.Errorf("%v can't be written as a Go literal", v)
+	}
+
+	s := strconv.FormatFloat(v, 'g', -1, bitSize)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s, nil
+}
+
+// AddLabel adds a label comment line to the doc of a package level variable or constant;
+// changes are kept in memory until WriteFiles is called
+func AddLabel(g *GoParser, name, label string) error {
+	f, spec, _ := findValueSpec(g, name)
+	if spec == nil {
+		return fmt.Errorf("%q not found", name)
+	}
+
+	var pos token.Pos
+	if decl := f.genDecl(spec); decl != nil && !decl.Lparen.IsValid() {
+		pos = decl.Pos()
+	} else {
+		pos = spec.Pos()
+	}
+
+	start := f.offset(pos)
+	lineStart := strings.LastIndexByte(string(f.src[:start]), '\n') + 1
+	indent := f.src[lineStart:start]
+
+	g.addEdit(f, start, start, "// "+label+"\n"+string(indent))
+
+	return nil
+}
+
+func (g *GoParser) addEdit(f *file, start, end int, text string) {
+	if g.edits == nil {
+		g.edits = make(map[*file][]edit)
+	}
+
+	for i, e := range g.edits[f] {
+		if e.start == start && e.end == end && start != end {
+			g.edits[f][i].text = text
+			return
+		}
+	}
+
+	g.edits[f] = append(g.edits[f], edit{start: start, end: end, text: text})
+}
+
+// WriteFiles writes all pending changes made by SetValue, AddLabel, etc. in a transaction:
+// changed files are formatted (see WithFormatter), checked to re-parse and staged next to the originals,
+// then renamed into place; if any step fails, already replaced files are restored and pending changes are discarded
+func WriteFiles(g *GoParser) error {
+	if len(g.edits) == 0 {
+		return nil
+	}
+
+	defer func() {
+		g.edits = nil
+	}()
+
+	if g.opts.preprocess != nil {
+		return errors.New("can't write back preprocessed files")
+	}
+
+	for f := range g.edits {
+		if f.inMemory {
+			return fmt.Errorf("can't write back in-memory source %s", f.path)
+		}
+	}
+
+	type staged struct {
+		f       *file
+		src     []byte
+		tmpPath string
+		bakPath string
+		done    bool
+	}
+
+	files := make([]*staged, 0, len(g.edits))
+
+	cleanup := func() {
+		for _, s := range files {
+			if s.tmpPath != "" {
+				_ = os.Remove(s.tmpPath)
+			}
+		}
+	}
+
+	for _, f := range g.files {
+		edits, ok := g.edits[f]
+		if !ok {
+			continue
+		}
+
+		src, err := g.opts.format(applyEdits(f.src, edits))
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+
+		if _, err = parser.ParseFile(token.NewFileSet(), f.path, src, parser.ParseComments); err != nil {
+			cleanup()
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+
+		s := &staged{f: f, src: src}
+		files = append(files, s)
+
+		if s.tmpPath, err = writeTemp(f.path, src); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	rollback := func() {
+		for _, s := range files {
+			if s.done {
+				_ = rename(s.bakPath, s.f.path)
+			}
+		}
+		cleanup()
+	}
+
+	for _, s := range files {
+		s.bakPath = s.tmpPath + ".bak"
+
+		if err := rename(s.f.path, s.bakPath); err != nil {
+			rollback()
+			return err
+		}
+
+		s.done = true
+
+		if err := rename(s.tmpPath, s.f.path); err != nil {
+			rollback()
+			return err
+		}
+
+		s.tmpPath = ""
+	}
+
+	for _, s := range files {
+		_ = os.Remove(s.bakPath)
+
+		fileAst, err := parser.ParseFile(s.f.fset, s.f.path, s.src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		s.f.ast, s.f.src = fileAst, s.src
+	}
+
+	return nil
+}
+
+// rename renames files of WriteFiles, it's replaced in tests to fail
+var rename = os.Rename
+
+// writeTemp writes the source to a temporary file in the directory of the path with the same permissions
+func writeTemp(path string, src []byte) (string, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = tmp.Write(src)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), stat.Mode().Perm())
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+
+	return tmp.Name(), nil
+}
+
+func applyEdits(src []byte, edits []edit) []byte {
+	// edits are applied from the end, so that offsets of the rest stay valid; at the same offset
+	// a replacement is applied before inserts and inserts are applied in reverse, so that their texts
+	// keep the order the edits were made in
+	order := make([]int, len(edits))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(a, b int) bool {
+		x, y := edits[order[a]], edits[order[b]]
+		if x.start != y.start {
+			return x.start > y.start
+		}
+
+		if xInsert, yInsert := x.start == x.end, y.start == y.end; xInsert != yInsert {
+			return yInsert
+		}
+
+		return order[a] > order[b]
+	})
+
+	result := append([]byte(nil), src...)
+	for _, i := range order {
+		e := edits[i]
+		result = append(result[:e.start], append([]byte(e.text), result[e.end:]...)...)
+	}
+
+	return result
+}
+
+// findValueSpec returns a package level value spec declaring the name and the index of the name in it
+func findValueSpec(g *GoParser, name string) (*file, *ast.ValueSpec, int) {
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+
+			for _, spec := range decl.Specs {
+				s, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+
+				for i, n := range s.Names {
+					if n.Name == name {
+						return f, s, i
+					}
+				}
+			}
+		}
+	}
+
+	return nil, nil, 0
+}
+
+// genDecl returns the declaration containing the spec
+func (f *file) genDecl(spec ast.Spec) *ast.GenDecl {
+	for _, d := range f.ast.Decls {
+		if decl, ok := d.(*ast.GenDecl); ok {
+			for _, s := range decl.Specs {
+				if s == spec {
+					return decl
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (f *file) offset(pos token.Pos) int {
+	return f.fset.File(pos).Offset(pos)
+}
@@ -0,0 +1,151 @@
+package goparser
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyEdits(t *testing.T) {
+	src := []byte("var x = 1\n")
+
+	tests := []struct {
+		name  string
+		edits []edit
+		want  string
+	}{
+		{
+			name:  "inserts at the same offset",
+			edits: []edit{{start: 0, end: 0, text: "// a\n"}, {start: 0, end: 0, text: "// b\n"}},
+			want:  "// a\n// b\nvar x = 1\n",
+		},
+		{
+			name:  "replacement and inserts",
+			edits: []edit{{start: 8, end: 9, text: "2"}, {start: 0, end: 0, text: "// a\n"}, {start: 8, end: 8, text: "-"}},
+			want:  "// a\nvar x = -2\n",
+		},
+		{
+			name:  "insert before a replacement at the same offset",
+			edits: []edit{{start: 8, end: 8, text: "-"}, {start: 8, end: 9, text: "2"}},
+			want:  "var x = -2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(applyEdits(src, tt.edits)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddLabelOrder(t *testing.T) {
+	p := newTestParser(t, "package p\n\nvar x = 1\n")
+	path := p.files[0].path
+
+	for _, l := range []string{"first", "second"} {
+		if err := AddLabel(p, "x", l); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := WriteFiles(p); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "package p\n\n// first\n// second\nvar x = 1\n"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSetBasicValueNonFinite(t *testing.T) {
+	p := newTestParser(t, "package p\n\nvar x = 1.5\n")
+
+	for _, v := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		if err := SetBasicValue(p, "x", v); err == nil {
+			t.Errorf("%v is set", v)
+		}
+	}
+
+	if len(p.edits) != 0 {
+		t.Errorf("got edits %v", p.edits)
+	}
+}
+
+func TestWriteFilesRollback(t *testing.T) {
+	dir := t.TempDir()
+
+	srcs := map[string]string{
+		"a.go": "package p\n\nvar a = 1\n",
+		"b.go": "package p\n\nvar b = 2\n",
+	}
+	for name, src := range srcs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := NewFromDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = SetValue(p, "a", "10"); err != nil {
+		t.Fatal(err)
+	}
+	if err = SetValue(p, "b", "20"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the second file fails to be renamed into place after the first one is replaced
+	errRename := errors.New("rename failed")
+	rename = func(oldPath, newPath string) error {
+		if filepath.Base(newPath) == "b.go" && strings.HasPrefix(filepath.Base(oldPath), ".b.go.") && !strings.HasSuffix(oldPath, ".bak") {
+			return errRename
+		}
+		return os.Rename(oldPath, newPath)
+	}
+	defer func() {
+		rename = os.Rename
+	}()
+
+	if err = WriteFiles(p); !errors.Is(err, errRename) {
+		t.Fatalf("got error %v, want %v", err, errRename)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != len(srcs) {
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("got files %v, temporary files are left", names)
+	}
+
+	for name, want := range srcs {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s: got %q, want the original %q", name, got, want)
+		}
+	}
+
+	if len(p.edits) != 0 {
+		t.Errorf("pending edits aren't discarded: %v", p.edits)
+	}
+}
@@ -0,0 +1,197 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// EnumValue contains a labeled integer constant with its computed value, e.g. of an iota-based const block;
+// the type of the constant, e.g. "Color", is in DeclInfo.Type
+type EnumValue struct {
+	DeclInfo
+	Value int64
+}
+
+// GetEnumValues returns labeled integer constants with their computed values by godoc label;
+// iota and constant expressions are evaluated, implicitly repeated expressions of a const block are taken into account
+//
+//	type Color int
+//
+//	// someLabel
+//	const (
+//	    Red Color = iota + 1 // 1
+//	    Green                // 2
+//	    Blue                 // 3
+//	)
+func GetEnumValues(g *GoParser, docLabels ...string) []EnumValue {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return walkDecls(g, docMap, func(info DeclInfo, val ast.Expr) *EnumValue {
+		lit, ok := basicLit(val)
+		if !ok || lit.Kind != token.INT {
+			return nil
+		}
+
+		v, err := strconv.ParseInt(lit.Value, 0, 64)
+		if err != nil {
+			return nil
+		}
+
+		return &EnumValue{DeclInfo: info, Value: v}
+	})
+}
+
+// SwitchReport contains a switch statement over an enum type missing some of its constants
+type SwitchReport struct {
+	// Func is the name of the enclosing function, methods are named as "Type.Method"
+	Func string
+
+	Pos        token.Position
+	Missing    []string
+	HasDefault bool
+}
+
+// GetMissingCases finds switch statements over an enum type (a type with a block of constants, usually iota-based)
+// and reports ones missing some of the constants;
+// in typed mode switches are detected by the tag type and constants are compared by value,
+// otherwise a switch is considered to be over the type if any of its cases is a constant of the type
+func GetMissingCases(g *GoParser, typeName string) []SwitchReport {
+	consts := enumConsts(g, typeName)
+	if len(consts) == 0 {
+		return nil
+	}
+
+	result := make([]SwitchReport, 0)
+
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Body == nil {
+				continue
+			}
+
+			ast.Inspect(decl.Body, func(n ast.Node) bool {
+				sw, ok := n.(*ast.SwitchStmt)
+				if !ok || sw.Tag == nil {
+					return true
+				}
+
+				if g.types != nil && !isNamed(g.types.info.TypeOf(sw.Tag), typeName) {
+					return true
+				}
+
+				covered := make(map[string]struct{})
+				matched, hasDefault := false, false
+
+				for _, s := range sw.Body.List {
+					cc := s.(*ast.CaseClause)
+					if cc.List == nil {
+						hasDefault = true
+					}
+
+					for _, e := range cc.List {
+						id, ok := e.(*ast.Ident)
+						if !ok {
+							continue
+						}
+
+						if _, ok = consts[id.Name]; ok {
+							matched = true
+							covered[g.constKey(id, id.Name)] = struct{}{}
+						}
+					}
+				}
+
+				if !matched && g.types == nil {
+					return true
+				}
+
+				missing := make([]string, 0)
+				for _, name := range sortedKeys(consts) {
+					if _, ok := covered[g.constKey(consts[name], name)]; !ok {
+						missing = append(missing, name)
+					}
+				}
+
+				if len(missing) > 0 {
+					result = append(result, SwitchReport{
+						Func:       funcName(decl),
+						Pos:        f.fset.Position(sw.Pos()),
+						Missing:    missing,
+						HasDefault: hasDefault,
+					})
+				}
+
+				return true
+			})
+		}
+	}
+
+	return result
+}
+
+// constKey returns the constant value in typed mode and the name otherwise
+func (g *GoParser) constKey(id *ast.Ident, name string) string {
+	if g.types != nil {
+		if c, ok := g.types.info.ObjectOf(id).(*types.Const); ok {
+			return c.Val().ExactString()
+		}
+	}
+	return name
+}
+
+func isNamed(t types.Type, name string) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == name
+}
+
+// enumConsts returns package level constants of the type by name with their identifiers;
+// constants without an explicit type in a const block inherit the type of the previous spec
+func enumConsts(g *GoParser, typeName string) map[string]*ast.Ident {
+	result := make(map[string]*ast.Ident)
+
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.GenDecl)
+			if !ok || decl.Tok != token.CONST {
+				continue
+			}
+
+			var typ ast.Expr
+			for _, spec := range decl.Specs {
+				s := spec.(*ast.ValueSpec)
+				if s.Type != nil || len(s.Values) > 0 {
+					typ = s.Type
+				}
+
+				if typ == nil && len(s.Values) == 1 {
+					// T(iota) or T("value")
+					if call, ok := s.Values[0].(*ast.CallExpr); ok && len(call.Args) == 1 {
+						typ = call.Fun
+					}
+				}
+
+				if id, ok := typ.(*ast.Ident); !ok || id.Name != typeName {
+					continue
+				}
+
+				for _, n := range s.Names {
+					if n.Name != "_" {
+						result[n.Name] = n
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,55 @@
+package goparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetEnumValues(t *testing.T) {
+	// the example of GetEnumValues
+	const src = `package p
+
+type Color int
+
+// someLabel
+const (
+	Red Color = iota + 1 // 1
+	Green                // 2
+	Blue                 // 3
+)
+
+// someLabel
+const (
+	Small = 1
+	Large = 2
+)
+`
+
+	tests := []struct {
+		name   string
+		policy DocPolicy
+	}{
+		{name: "default", policy: DefaultDocPolicy},
+		{name: "run", policy: DefaultDocPolicy | DocRun},
+	}
+
+	want := map[string]int64{"Red": 1, "Green": 2, "Blue": 3}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParser(t, src, WithDocPolicy(tt.policy))
+
+			got := make(map[string]int64)
+			for _, v := range GetEnumValues(p, "someLabel") {
+				got[v.Name] = v.Value
+				if v.Type != "Color" {
+					t.Errorf("%s: got type %q, want Color", v.Name, v.Type)
+				}
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
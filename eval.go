@@ -0,0 +1,198 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"unicode"
+)
+
+// maxShift limits shift counts of evaluated constant expressions
+const maxShift = 1023
+
+// evalConst evaluates a constant expression of literals and package-level constants and variables of the file
+// (followed up to the depth) with go/constant; iota is the value of iota in a const declaration, -1 outside of them:
+//
+//	var a = 60 * 60 * 24 // 86400
+//	var b = 1 << 20      // 1048576
+//	var c = size * 2     // 1024 with const size = 512
+func (f *file) evalConst(expr ast.Expr, depth, iota int) (constant.Value, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		v := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		return v, v.Kind() != constant.Unknown
+	case *ast.Ident:
+		if b, ok := boolIdent(e); ok {
+			return constant.MakeBool(b), true
+		}
+
+		if e.Name == "iota" && e.Obj == nil && iota >= 0 {
+			return constant.MakeInt64(int64(iota)), true
+		}
+
+		if depth <= 0 {
+			return nil, false
+		}
+
+		next := f.initExpr(e)
+		if next == nil {
+			return nil, false
+		}
+
+		return f.evalConst(next, depth-1, -1) // iota of the referenced spec is unknown
+	case *ast.ParenExpr:
+		return f.evalConst(e.X, depth, iota)
+	case *ast.CallExpr:
+		if len(e.Args) != 1 || e.Ellipsis.IsValid() || !f.isTypeName(e.Fun) {
+			return nil, false
+		}
+
+		x, ok := f.evalConst(e.Args[0], depth, iota)
+		if !ok {
+			return nil, false
+		}
+
+		return f.convertConst(e.Fun, x)
+	case *ast.UnaryExpr:
+		x, ok := f.evalConst(e.X, depth, iota)
+		if !ok {
+			return nil, false
+		}
+
+		return unaryOp(e.Op, x)
+	case *ast.BinaryExpr:
+		x, ok := f.evalConst(e.X, depth, iota)
+		if !ok {
+			return nil, false
+		}
+
+		y, ok := f.evalConst(e.Y, depth, iota)
+		if !ok {
+			return nil, false
+		}
+
+		return binaryOp(x, e.Op, y)
+	}
+
+	return nil, false
+}
+
+// convertConst converts the value to the basic type the type is or is declared with in the file (see basicTypeName),
+// integers are converted to strings as runes; values of other types are returned as is
+//
+//	float32(3)       // 3.0
+//	string(rune(65)) // "A"
+func (f *file) convertConst(typ ast.Expr, x constant.Value) (constant.Value, bool) {
+	switch f.basicTypeName(typ) {
+	case "":
+		return x, true
+	case "float32", "float64":
+		if !isNumeric(x) {
+			return nil, false
+		}
+		return constant.ToFloat(x), true
+	case "complex64", "complex128":
+		x = constant.ToComplex(x)
+		return x, x.Kind() == constant.Complex
+	case "string":
+		switch x.Kind() {
+		case constant.String:
+			return x, true
+		case constant.Int:
+			r, ok := constant.Int64Val(x)
+			if !ok || r < 0 || r > unicode.MaxRune {
+				r = unicode.ReplacementChar
+			}
+			return constant.MakeString(string(rune(r))), true
+		}
+		return nil, false
+	case "bool":
+		return x, x.Kind() == constant.Bool
+	}
+
+	// integer types
+	x = constant.ToInt(x)
+	return x, x.Kind() == constant.Int
+}
+
+func unaryOp(op token.Token, x constant.Value) (constant.Value, bool) {
+	switch op {
+	case token.ADD, token.SUB:
+		if !isNumeric(x) {
+			return nil, false
+		}
+	case token.XOR:
+		if x.Kind() != constant.Int {
+			return nil, false
+		}
+	case token.NOT:
+		if x.Kind() != constant.Bool {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	return constant.UnaryOp(op, x, 0), true
+}
+
+// binaryOp applies the operator to the values, false is returned for operands the operator isn't defined for
+func binaryOp(x constant.Value, op token.Token, y constant.Value) (constant.Value, bool) {
+	switch op {
+	case token.SHL, token.SHR:
+		x, s := constant.ToInt(x), constant.ToInt(y)
+		if x.Kind() != constant.Int || s.Kind() != constant.Int {
+			return nil, false
+		}
+
+		n, ok := constant.Uint64Val(s)
+		if !ok || n > maxShift {
+			return nil, false
+		}
+
+		return constant.Shift(x, op, uint(n)), true
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		if !comparable(x, y) || x.Kind() == constant.Bool && op != token.EQL && op != token.NEQ {
+			return nil, false
+		}
+
+		return constant.MakeBool(constant.Compare(x, op, y)), true
+	case token.LAND, token.LOR:
+		if x.Kind() != constant.Bool || y.Kind() != constant.Bool {
+			return nil, false
+		}
+	case token.ADD:
+		if !(isNumeric(x) && isNumeric(y)) && !(x.Kind() == constant.String && y.Kind() == constant.String) {
+			return nil, false
+		}
+	case token.SUB, token.MUL:
+		if !isNumeric(x) || !isNumeric(y) {
+			return nil, false
+		}
+	case token.QUO:
+		if !isNumeric(x) || !isNumeric(y) || constant.Sign(y) == 0 {
+			return nil, false
+		}
+
+		if x.Kind() == constant.Int && y.Kind() == constant.Int {
+			op = token.QUO_ASSIGN // integer division
+		}
+	case token.REM, token.AND, token.OR, token.XOR, token.AND_NOT:
+		if x.Kind() != constant.Int || y.Kind() != constant.Int || op == token.REM && constant.Sign(y) == 0 {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	return constant.BinaryOp(x, op, y), true
+}
+
+func isNumeric(v constant.Value) bool {
+	return v.Kind() == constant.Int || v.Kind() == constant.Float
+}
+
+// comparable reports whether the values can be compared with constant.Compare
+func comparable(x, y constant.Value) bool {
+	return isNumeric(x) && isNumeric(y) || x.Kind() == y.Kind() && x.Kind() != constant.Unknown
+}
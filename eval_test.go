@@ -0,0 +1,52 @@
+package goparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertConst(t *testing.T) {
+	p := newTestParser(t, `package p
+
+type Name string
+
+const size = 512
+
+// p
+var a = string(rune(65))
+
+// p
+var b = Name(rune(0x42))
+
+// p
+var c = float64(size / 2)
+
+// p
+var d = int64(size) * 2
+
+// p
+var e = bool(size > 100)
+
+// p
+var f = string(rune(-1))
+`)
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "string", got: basicValues(GetBasicValues[string](p, "p")), want: map[string]string{"a": "A", "b": "B", "f": "�"}},
+		{name: "float64", got: basicValues(GetBasicValues[float64](p, "p")), want: map[string]float64{"c": 256}},
+		{name: "int", got: basicValues(GetBasicValues[int](p, "p")), want: map[string]int{"d": 1024}},
+		{name: "bool", got: basicValues(GetBasicValues[bool](p, "p")), want: map[string]bool{"e": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -25,6 +25,8 @@ func main() {
 	floatValues := gp.GetBasicValues[float64](p, "parser")
 	for _, v := range floatValues {
 		fmt.Printf("name: %s; value: %.02f\n", v.Name, v.Value) // name: float64Value; value: 3.14
+		// name: float32Value; value: 3.14
+		// name: floatConstValue; value: 0.00
 	}
 
 	floatSliceValues := gp.GetSliceValues[float64](p, "parser")
@@ -40,6 +42,7 @@ func main() {
 	stringMapValues := gp.GetMapValues[string, string](p, "parser", "parser:str")
 	for _, v := range stringMapValues {
 		fmt.Printf("name: %s; values: %+v\n", v.Name, v.Value) // name: stringToStringMapValue; values: map[a:1 b:2]
+		// name: pointerMapValue; values: map[c:3]
 	}
 
 	floatMapValues := gp.GetMapValues[int64, float64](p, "parser")
@@ -22,10 +22,10 @@ var (
 	float64Value = 3.14
 
 	// parser
-	float32Value = float32(3.14) // not implemented yet
+	float32Value = float32(3.14)
 
 	// parser
-	notParsedFloatValue = floatConst // not implemented yet
+	floatConstValue = floatConst // resolved to the value of floatConst
 
 	// parser
 	float64SliceValue = []float64{3.14, 0.42}
@@ -37,13 +37,13 @@ var (
 	stringToStringMapValue = map[string]string{"a": "1", "b": "2"}
 
 	// parser
-	pointerMapValue = &map[string]string{} // not implemented yet
+	pointerMapValue = &map[string]string{"c": "3"}
 
 	// parser
 	intToFloat64MapValue = map[int]float64{3: 3.14, 17: 42.0}
 
 	_, _, _, _, _, _, _, _, _, _, _ = stringValue, intValue, notParsedInValue, float64Value, float32Value,
-		notParsedFloatValue, float64SliceValue, stringSliceValue, stringToStringMapValue, pointerMapValue,
+		floatConstValue, float64SliceValue, stringSliceValue, stringToStringMapValue, pointerMapValue,
 		intToFloat64MapValue
 )
 
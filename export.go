@@ -0,0 +1,337 @@
+package goparser
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// SchemaVersion is the version of the JSON schema of exported reports (see JSONSchema);
+// the major version is incremented on breaking changes, the minor one on backward compatible additions
+const SchemaVersion = "1.15.0"
+
+//go:embed schema.json
+var jsonSchema []byte
+
+// JSONSchema returns the JSON schema of exported reports
+func JSONSchema() []byte {
+	return append([]byte(nil), jsonSchema...)
+}
+
+// Result kinds
+const (
+	KindBasic = "basic"
+	KindSlice = "slice"
+	KindMap   = "map"
+
+	// KindSliceMap is a slice of maps, its value is a slice of map entries slices
+	KindSliceMap = "sliceMap"
+
+	// KindSelectorRef is a textual reference to a qualified identifier, e.g. somepkg.SomeConst (see SelectorRef)
+	KindSelectorRef = "selectorRef"
+
+	// KindAlloc is a value initialized with make or new, its value is the source of the call (see AllocValue)
+	KindAlloc = "alloc"
+)
+
+// Result contains an extracted value of any type
+type Result struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// Doc is the first matched label
+	//
+	// Deprecated: use Label, Doc is kept for compatibility and equals Label.
+	Doc string `json:"doc"`
+
+	// Label is the first matched label
+	Label string `json:"label,omitempty"`
+
+	Labels   []string `json:"labels,omitempty"`
+	Checksum string   `json:"checksum"`
+
+	// Description is the text of the doc comment without matched label lines
+	Description string `json:"description,omitempty"`
+
+	// Args contains label arguments, e.g. owner (see ArgOwner)
+	Args map[string]string `json:"args,omitempty"`
+
+	// Type is the declared type of the declaration, e.g. "time.Duration",
+	// or the type of the conversion the value is written as, e.g. "float32" of float32(3.14)
+	Type string `json:"type,omitempty"`
+
+	// Func is the enclosing function of a declaration in a function body, see WithFuncBodies
+	Func string `json:"func,omitempty"`
+
+	// Package is the import path of the declaring package, see WithModule
+	Package string `json:"package,omitempty"`
+
+	// File and Line are the position of the declaration
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	// LabelLine is the line of the first matched label comment
+	LabelLine int `json:"labelLine,omitempty"`
+
+	// Usage is the position of the first reference to the value, see WithUsages
+	Usage string `json:"usage,omitempty"`
+
+	// Truncated is set when some elements of the value are omitted because of limits
+	Truncated bool `json:"truncated,omitempty"`
+
+	// DocTruncated is set when the doc comment isn't scanned for labels completely because of limits
+	DocTruncated bool `json:"docTruncated,omitempty"`
+
+	// Value is a basic value for KindBasic, a slice of basic values for KindSlice
+	// a slice of entries sorted by key for KindMap, a slice of such slices for KindSliceMap
+	// a qualified identifier string for KindSelectorRef and the source of the call for KindAlloc
+	Value any `json:"value"`
+
+	// Format describes how an integer basic value is written in the source
+	Format *NumFormat `json:"format,omitempty"`
+}
+
+// Entry contains a key and a value of a map
+type Entry struct {
+	Key   any `json:"key"`
+	Value any `json:"value"`
+
+	// Format describes how an integer basic value is written in the source
+	Format *NumFormat `json:"format,omitempty"`
+}
+
+// Exportable is implemented by all extracted value types
+type Exportable interface {
+	Result() Result
+}
+
+// Result returns the value as Result
+func (v LitValue[V]) Result() Result {
+	return withFormat(newResult(KindBasic, v.DeclInfo, v.Value), v.Format)
+}
+
+// Result returns the value as Result
+func (v SliceLitValue[V]) Result() Result {
+	return newResult(KindSlice, v.DeclInfo, v.Value)
+}
+
+// Result returns the value as Result
+func (v MapLitValue[K, V]) Result() Result {
+	return newResult(KindMap, v.DeclInfo, withEntryFormats(mapEntries(reflect.ValueOf(v.Value)), v.Formats))
+}
+
+// mapEntries returns entries of the map sorted by key
+func mapEntries(m reflect.Value) []Entry {
+	entries := make([]Entry, 0, m.Len())
+
+	iter := m.MapRange()
+	for iter.Next() {
+		entries = append(entries, Entry{Key: iter.Key().Interface(), Value: iter.Value().Interface()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return lessAny(entries[i].Key, entries[j].Key)
+	})
+
+	return entries
+}
+
+// lessAny compares basic values of the same kind, falling back to comparing their string representations
+func lessAny(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() == vb.Kind() {
+		switch va.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return va.Int() < vb.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return va.Uint() < vb.Uint()
+		case reflect.Float32, reflect.Float64:
+			return va.Float() < vb.Float()
+		case reflect.String:
+			return va.String() < vb.String()
+		case reflect.Bool:
+			return !va.Bool() && vb.Bool()
+		}
+	}
+
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// withEntryFormats sets formats of the entries by their keys
+func withEntryFormats[K iLit](entries []Entry, formats map[K]NumFormat) []Entry {
+	for i, e := range entries {
+		if format, ok := formats[e.Key.(K)]; ok {
+			entries[i].Format = &format
+		}
+	}
+	return entries
+}
+
+func withFormat(r Result, format NumFormat) Result {
+	if format.Base != 0 {
+		r.Format = &format
+	}
+	return r
+}
+
+func newResult(kind string, info DeclInfo, value any) Result {
+	return Result{
+		Kind:         kind,
+		Name:         info.Name,
+		Doc:          info.Doc,
+		Label:        info.Label,
+		Labels:       info.Labels,
+		Description:  info.Description,
+		Checksum:     info.Checksum,
+		Args:         info.Args,
+		Type:         info.Type,
+		Func:         info.Func,
+		Package:      info.Package,
+		Truncated:    info.Truncated,
+		DocTruncated: info.DocTruncated,
+		File:         info.Pos.Filename,
+		Line:         info.Pos.Line,
+		LabelLine:    info.LabelPos.Line,
+		Usage:        usagePos(info.Usage),
+		Value:        value,
+	}
+}
+
+func usagePos(pos token.Position) string {
+	if !pos.IsValid() {
+		return ""
+	}
+	return pos.String()
+}
+
+// Report contains exported results
+type Report struct {
+	SchemaVersion string   `json:"schemaVersion"`
+	Results       []Result `json:"results"`
+}
+
+// NewReport returns a new empty Report of the current schema version
+func NewReport() *Report {
+	return &Report{
+		SchemaVersion: SchemaVersion,
+		Results:       make([]Result, 0),
+	}
+}
+
+// AddResults appends the values to the report
+//
+//	r := NewReport()
+//	AddResults(r, GetBasicValues[string](p, "parser"))
+//	AddResults(r, GetSliceValues[int64](p, "parser"))
+func AddResults[T Exportable](r *Report, values []T) {
+	for _, v := range values {
+		r.Results = append(r.Results, v.Result())
+	}
+}
+
+// Dedupe merges results of the same declaration into the first one, aggregating their labels
+func (r *Report) Dedupe() {
+	type key struct {
+		file string
+		line int
+		name string
+	}
+
+	results := make([]Result, 0, len(r.Results))
+	index := make(map[key]int)
+
+	for _, res := range r.Results {
+		k := key{file: res.File, line: res.Line, name: res.Name}
+
+		i, ok := index[k]
+		if !ok || res.File == "" {
+			index[k] = len(results)
+			results = append(results, res)
+			continue
+		}
+
+		results[i].Labels = mergeLabels(results[i].Labels, res.Labels)
+	}
+
+	r.Results = results
+}
+
+// CollectValues returns a report of labeled values of common types: basic values with inferred types (see InferValues),
+// slices of strings, int64 and float64 and maps of them; values of the same declaration are merged
+func CollectValues(g *GoParser, labels ...string) *Report {
+	r := NewReport()
+
+	AddResults(r, InferValues(g, labels...))
+
+	AddResults(r, GetSliceValues[string](g, labels...))
+	AddResults(r, GetSliceValues[int64](g, labels...))
+	AddResults(r, GetSliceValues[float64](g, labels...))
+
+	AddResults(r, GetMapValues[string, string](g, labels...))
+	AddResults(r, GetMapValues[string, int64](g, labels...))
+	AddResults(r, GetMapValues[string, float64](g, labels...))
+	AddResults(r, GetMapValues[int64, string](g, labels...))
+	AddResults(r, GetMapValues[int64, int64](g, labels...))
+	AddResults(r, GetMapValues[int64, float64](g, labels...))
+
+	AddResults(r, GetSliceMapValues[string, string](g, labels...))
+	AddResults(r, GetSliceMapValues[string, int64](g, labels...))
+
+	AddResults(r, GetAllocValues(g, labels...))
+
+	// empty composite literals match every element type
+	r.Dedupe()
+
+	return r
+}
+
+// Group contains results with the same value of a label argument
+type Group struct {
+	Key     string   `json:"key"`
+	Results []Result `json:"results"`
+}
+
+// GroupBy groups results by the value of the label argument, groups are sorted by key;
+// results without the argument are in the group with an empty key
+func (r *Report) GroupBy(arg string) []Group {
+	idx := make(map[string]int)
+	groups := make([]Group, 0)
+
+	for _, res := range r.Results {
+		key := res.Args[arg]
+
+		i, ok := idx[key]
+		if !ok {
+			i = len(groups)
+			idx[key] = i
+			groups = append(groups, Group{Key: key})
+		}
+
+		groups[i].Results = append(groups[i].Results, res)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Key < groups[j].Key
+	})
+
+	return groups
+}
+
+// ByOwner groups results by their owner label argument, e.g. for per-team audits
+//
+//	// parser owner=team-a
+//	var timeout = 30
+func (r *Report) ByOwner() []Group {
+	return r.GroupBy(ArgOwner)
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,100 @@
+package goparser
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEntryFormats(t *testing.T) {
+	p := newTestParser(t, `package p
+
+// cfg
+var masks = map[string]int64{"a": 0xFF, "b": 10, "c": 0b1010_1010}
+
+// cfg
+var rows = []map[string]int64{{"a": 0o17}, {"a": 1}}
+`)
+
+	formats := func(entries []Entry) map[any]string {
+		m := make(map[any]string)
+		for _, e := range entries {
+			if e.Format != nil {
+				m[e.Key] = formatIntLit(e.Value, *e.Format, "")
+			}
+		}
+		return m
+	}
+
+	maps := GetMapValues[string, int64](p, "cfg")
+	if len(maps) != 1 {
+		t.Fatalf("got %d map values, want 1", len(maps))
+	}
+
+	got := formats(maps[0].Result().Value.([]Entry))
+	want := map[any]string{"a": "0xFF", "b": "10", "c": "0b1010_1010"}
+	if len(got) != len(want) {
+		t.Errorf("got formatted entries %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%v: got %s, want %s", k, got[k], v)
+		}
+	}
+
+	rows := GetSliceMapValues[string, int64](p, "cfg")
+	if len(rows) != 1 {
+		t.Fatalf("got %d slice map values, want 1", len(rows))
+	}
+
+	entries := rows[0].Result().Value.([][]Entry)
+	if got := formats(entries[0])["a"]; got != "0o17" {
+		t.Errorf("got %s, want 0o17", got)
+	}
+
+	results := CollectValues(p, "cfg").Results
+
+	f := NewGenFile("p")
+	if err := GenerateDefaults(f, results); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"a": 0xFF`, `"c": 0b1010_1010`, `{"a": 0o17}`} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("no %s in\n%s", want, src)
+		}
+	}
+
+	mismatches, err := CheckRoundTrip(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) > 0 {
+		t.Errorf("got mismatches %+v", mismatches)
+	}
+}
+
+func TestJSONSchemaEntryFormat(t *testing.T) {
+	var schema struct {
+		Defs struct {
+			Entries struct {
+				Items struct {
+					Properties map[string]json.RawMessage `json:"properties"`
+				} `json:"items"`
+			} `json:"entries"`
+		} `json:"$defs"`
+	}
+
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := schema.Defs.Entries.Items.Properties["format"]; !ok {
+		t.Error("no format of entries in the schema")
+	}
+}
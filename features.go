@@ -0,0 +1,189 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"go/version"
+	"strings"
+)
+
+// Language features reported by GetFeatures
+const (
+	FeatureGenerics         = "generics"
+	FeatureGenericReceivers = "genericReceivers"
+	FeatureGenericAliases   = "genericAliases"
+	FeatureAny              = "any"
+	FeatureNumberLiterals   = "numberLiterals"
+	FeatureMinMaxBuiltins   = "minMaxBuiltins"
+	FeatureClearBuiltin     = "clearBuiltin"
+	FeatureRangeOverInt     = "rangeOverInt"
+	FeatureRangeOverFunc    = "rangeOverFunc"
+)
+
+// featureVersions contains Go versions that introduced the features
+var featureVersions = map[string]string{
+	FeatureNumberLiterals:   "go1.13",
+	FeatureGenerics:         "go1.18",
+	FeatureGenericReceivers: "go1.18",
+	FeatureAny:              "go1.18",
+	FeatureMinMaxBuiltins:   "go1.21",
+	FeatureClearBuiltin:     "go1.21",
+	FeatureRangeOverInt:     "go1.22",
+	FeatureRangeOverFunc:    "go1.23",
+	FeatureGenericAliases:   "go1.24",
+}
+
+// Feature contains a language feature used in a file
+type Feature struct {
+	Name string
+
+	// GoVersion is the Go version that introduced the feature, e.g. "go1.18"
+	GoVersion string
+
+	// Pos is the position of the first use of the feature in the file
+	Pos token.Position
+}
+
+// FileFeatures contains language features used in a file
+type FileFeatures struct {
+	File     string
+	Features []Feature
+
+	// GoVersion is the minimum Go version required by the features, empty if the file uses none of them
+	GoVersion string
+}
+
+// GetFeatures reports language features each parsed file uses, e.g. to check files against a Go version:
+// generics, generic method receivers and type aliases, the predeclared any, binary and octal literals
+// and digit separators, min, max and clear builtins and range over integers and functions;
+// without type information builtins are detected by unresolved names and ranges by integer literals and function literals
+func GetFeatures(g *GoParser) []FileFeatures {
+	result := make([]FileFeatures, 0, len(g.files))
+
+	for _, f := range g.files {
+		ff := FileFeatures{File: f.path, Features: make([]Feature, 0)}
+		seen := make(map[string]struct{})
+
+		add := func(name string, pos token.Pos) {
+			if _, ok := seen[name]; ok {
+				return
+			}
+			seen[name] = struct{}{}
+
+			v := featureVersions[name]
+			ff.Features = append(ff.Features, Feature{Name: name, GoVersion: v, Pos: f.fset.Position(pos)})
+
+			if ff.GoVersion == "" || version.Compare(v, ff.GoVersion) > 0 {
+				ff.GoVersion = v
+			}
+		}
+
+		ast.Inspect(f.ast, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				if node.Type.TypeParams != nil {
+					add(FeatureGenerics, node.Type.TypeParams.Pos())
+				}
+				if node.Recv != nil && len(node.Recv.List) > 0 && isGenericRecv(node.Recv.List[0].Type) {
+					add(FeatureGenericReceivers, node.Recv.Pos())
+				}
+			case *ast.TypeSpec:
+				if node.TypeParams != nil {
+					add(FeatureGenerics, node.TypeParams.Pos())
+					if node.Assign.IsValid() {
+						add(FeatureGenericAliases, node.Pos())
+					}
+				}
+			case *ast.Ident:
+				if node.Name == "any" && node.Obj == nil {
+					add(FeatureAny, node.Pos())
+				}
+			case *ast.BasicLit:
+				if node.Kind == token.INT && isNewIntLit(node.Value) ||
+					node.Kind == token.FLOAT && strings.Contains(node.Value, "_") {
+					add(FeatureNumberLiterals, node.Pos())
+				}
+			case *ast.CallExpr:
+				if id, ok := node.Fun.(*ast.Ident); ok && g.isBuiltin(id) {
+					switch id.Name {
+					case "min", "max":
+						add(FeatureMinMaxBuiltins, id.Pos())
+					case "clear":
+						add(FeatureClearBuiltin, id.Pos())
+					}
+				}
+			case *ast.RangeStmt:
+				if name := g.rangeFeature(node.X); name != "" {
+					add(name, node.Pos())
+				}
+			}
+			return true
+		})
+
+		result = append(result, ff)
+	}
+
+	return result
+}
+
+// isGenericRecv reports whether the receiver type has type parameters, e.g. *Set[T]
+func isGenericRecv(t ast.Expr) bool {
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+
+	switch t.(type) {
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	}
+
+	return false
+}
+
+// isNewIntLit reports whether the integer literal uses syntax added in Go 1.13:
+// binary and 0o octal prefixes and digit separators
+func isNewIntLit(lit string) bool {
+	lower := strings.ToLower(lit)
+	return strings.HasPrefix(lower, "0b") || strings.HasPrefix(lower, "0o") || strings.Contains(lit, "_")
+}
+
+// isBuiltin reports whether the identifier refers to a builtin function
+func (g *GoParser) isBuiltin(id *ast.Ident) bool {
+	if g.types != nil {
+		if obj := g.types.info.Uses[id]; obj != nil {
+			_, ok := obj.(*types.Builtin)
+			return ok
+		}
+	}
+
+	return id.Obj == nil
+}
+
+// rangeFeature returns the feature of ranging over the expression, if any
+func (g *GoParser) rangeFeature(x ast.Expr) string {
+	if g.types != nil {
+		if t := g.types.info.TypeOf(x); t != nil {
+			switch u := t.Underlying().(type) {
+			case *types.Basic:
+				if u.Info()&types.IsInteger != 0 {
+					return FeatureRangeOverInt
+				}
+			case *types.Signature:
+				return FeatureRangeOverFunc
+			}
+			return ""
+		}
+	}
+
+	switch v := x.(type) {
+	case *ast.BasicLit:
+		if v.Kind == token.INT {
+			return FeatureRangeOverInt
+		}
+	case *ast.FuncLit:
+		return FeatureRangeOverFunc
+	}
+
+	return ""
+}
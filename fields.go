@@ -0,0 +1,92 @@
+package goparser
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// GetStructFields returns values of fields of struct literals by godoc label as separate values
+// named with dotted paths, nested struct literals are flattened; field types are inferred from literals (see InferValues)
+//
+//	// parser:defaults
+//	var Defaults = Config{Timeout: 30, DB: DBConfig{Host: "x"}} // Defaults.Timeout = int64(30), Defaults.DB.Host = "x"
+func GetStructFields(g *GoParser, docLabels ...string) []AnyValue {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return appendDeclsFunc(newResults[AnyValue](g), g, docMap, func(dst []AnyValue, f *file, info DeclInfo, val ast.Expr) []AnyValue {
+		lim := g.opts.newLimiter()
+
+		n := len(dst)
+		dst = g.appendFields(dst, f, info, info.Name, val, lim)
+
+		if lim.isTruncated() {
+			for i := n; i < len(dst); i++ {
+				dst[i].Truncated = true
+			}
+		}
+
+		return dst
+	})
+}
+
+// appendFields appends values of keyed fields of the struct literal named as prefix.Field
+func (g *GoParser) appendFields(dst []AnyValue, f *file, info DeclInfo, prefix string, val ast.Expr, lim *limiter) []AnyValue {
+	lit, ok := compositeLit(val)
+	if !ok || !isStructLit(lit) {
+		return dst
+	}
+
+	if !lim.enter() {
+		return dst
+	}
+	defer lim.leave()
+
+	for _, elt := range lit.Elts {
+		if !lim.add() {
+			break
+		}
+
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		fieldInfo := info
+		fieldInfo.Name = prefix + "." + key.Name
+		fieldInfo.Pos = f.fset.Position(key.Pos())
+		fieldInfo.Checksum = f.checksum(strings.Join(info.Labels, "\n"), fieldInfo.Name, kv.Value)
+
+		fieldVal := f.resolve(kv.Value, g.opts.resolveDepth)
+
+		if v := newAnyValue(f, fieldInfo, fieldVal); v != nil {
+			dst = append(dst, *v)
+			continue
+		}
+
+		dst = g.appendFields(dst, f, fieldInfo, fieldInfo.Name, fieldVal, lim)
+	}
+
+	return dst
+}
+
+// isStructLit reports whether the composite literal may be a struct literal:
+// its type is a struct type or a type name (possibly omitted in nested literals)
+func isStructLit(lit *ast.CompositeLit) bool {
+	switch lit.Type.(type) {
+	case *ast.StructType, *ast.Ident, *ast.SelectorExpr, nil:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// NumFormat describes how an integer literal is written in the source, so it can be re-emitted the same way
+type NumFormat struct {
+	// Base is 2, 8, 10 or 16
+	Base int `json:"base"`
+
+	// Prefix is the base prefix as written: "0x", "0X", "0b", "0B", "0o", "0O", "0" for legacy octal literals
+	// or an empty string for decimal ones
+	Prefix string `json:"prefix,omitempty"`
+
+	// Upper reports whether hexadecimal digits are upper case
+	Upper bool `json:"upper,omitempty"`
+
+	// Group is the number of digits between underscore separators, zero if there are no separators
+	Group int `json:"group,omitempty"`
+}
+
+// intFormat returns the format of the integer literal, a leading minus sign is ignored
+func intFormat(lit string) NumFormat {
+	f := NumFormat{Base: 10}
+
+	lit = strings.TrimPrefix(lit, "-")
+	digits := lit
+	if len(lit) > 1 && lit[0] == '0' {
+		switch lit[1] {
+		case 'x', 'X':
+			f.Base, f.Prefix = 16, lit[:2]
+		case 'b', 'B':
+			f.Base, f.Prefix = 2, lit[:2]
+		case 'o', 'O':
+			f.Base, f.Prefix = 8, lit[:2]
+		default:
+			f.Base, f.Prefix = 8, "0"
+		}
+		digits = lit[len(f.Prefix):]
+	}
+
+	if f.Base == 16 {
+		f.Upper = strings.ContainsAny(digits, "ABCDEF")
+	}
+
+	if i := strings.LastIndexByte(digits, '_'); i >= 0 {
+		f.Group = len(digits) - i - 1
+	}
+
+	return f
+}
+
+// litFormat returns the format of the value if it's an integer literal
+func litFormat(val ast.Expr) (NumFormat, bool) {
+	if lit, ok := basicLit(val); ok && lit.Kind == token.INT {
+		return intFormat(lit.Value), true
+	}
+	return NumFormat{}, false
+}
+
+// FormatInt formats the value as an integer literal of the format; negative values get a leading minus
+func (f NumFormat) FormatInt(v int64) string {
+	if v < 0 {
+		return "-" + f.FormatUint(uint64(-v))
+	}
+	return f.FormatUint(uint64(v))
+}
+
+// FormatUint formats the value as an integer literal of the format
+func (f NumFormat) FormatUint(v uint64) string {
+	base := f.Base
+	if base == 0 {
+		base = 10
+	}
+
+	digits := strconv.FormatUint(v, base)
+	if f.Upper {
+		digits = strings.ToUpper(digits)
+	}
+
+	if f.Group > 0 {
+		var sb strings.Builder
+		for i, d := range digits {
+			if i > 0 && (len(digits)-i)%f.Group == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(d)
+		}
+		digits = sb.String()
+	}
+
+	if f.Prefix == "0" && v == 0 {
+		return "0"
+	}
+
+	return f.Prefix + digits
+}
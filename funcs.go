@@ -0,0 +1,194 @@
+package goparser
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"strings"
+)
+
+// GetFuncBody returns the formatted body source and the body position of a function
+// or a method (the name is given as "Type.Method")
+func GetFuncBody(g *GoParser, name string) (src string, pos token.Position, err error) {
+	f, decl := findFunc(g, name)
+	if decl == nil {
+		return "", token.Position{}, fmt.Errorf("function %q not found", name)
+	}
+
+	if decl.Body == nil {
+		return "", token.Position{}, fmt.Errorf("function %q has no body", name)
+	}
+
+	var buf bytes.Buffer
+	if err = format.Node(&buf, f.fset, decl.Body); err != nil {
+		return "", token.Position{}, err
+	}
+
+	return buf.String(), f.fset.Position(decl.Body.Pos()), nil
+}
+
+// findFunc returns a function declaration by name, methods are named as "Type.Method"
+func findFunc(g *GoParser, name string) (*file, *ast.FuncDecl) {
+	recType, funcName := "", name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		recType, funcName = name[:i], name[i+1:]
+	}
+
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Name.Name != funcName || recvTypeName(decl) != recType {
+				continue
+			}
+
+			return f, decl
+		}
+	}
+
+	return nil, nil
+}
+
+// funcName returns the function name, methods are named as "Type.Method"
+func funcName(decl *ast.FuncDecl) string {
+	if rec := recvTypeName(decl); rec != "" {
+		return rec + "." + decl.Name.Name
+	}
+	return decl.Name.Name
+}
+
+// recvTypeName returns the receiver type name of a method or an empty string for a function
+func recvTypeName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+
+	t := decl.Recv.List[0].Type
+	if st, ok := t.(*ast.StarExpr); ok {
+		t = st.X
+	}
+
+	switch rType := t.(type) {
+	case *ast.Ident:
+		return rType.Name
+	case *ast.IndexExpr:
+		if id, ok := rType.X.(*ast.Ident); ok {
+			return id.Name
+		}
+	case *ast.IndexListExpr:
+		if id, ok := rType.X.(*ast.Ident); ok {
+			return id.Name
+		}
+	}
+
+	return ""
+}
+
+// StmtPredicate reports whether a statement matches a query
+type StmtPredicate func(stmt ast.Stmt) bool
+
+// FindStatements returns positions of statements of a function or a method (the name is given as "Type.Method")
+// matching the predicate, including statements of nested function literals
+//
+//	FindStatements(p, "LocalStruct.run", IsCallTo("panic", "log.Fatal"))
+func FindStatements(g *GoParser, funcName string, pred StmtPredicate) ([]token.Position, error) {
+	f, decl := findFunc(g, funcName)
+	if decl == nil {
+		return nil, fmt.Errorf("function %q not found", funcName)
+	}
+
+	result := make([]token.Position, 0)
+	if decl.Body == nil {
+		return result, nil
+	}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		stmt, ok := n.(ast.Stmt)
+		if ok && pred(stmt) {
+			result = append(result, f.fset.Position(stmt.Pos()))
+		}
+		return true
+	})
+
+	return result, nil
+}
+
+// IsReturn matches return statements
+func IsReturn(stmt ast.Stmt) bool {
+	_, ok := stmt.(*ast.ReturnStmt)
+	return ok
+}
+
+// IsDefer matches defer statements
+func IsDefer(stmt ast.Stmt) bool {
+	_, ok := stmt.(*ast.DeferStmt)
+	return ok
+}
+
+// IsCallTo returns a predicate matching call statements (including deferred and go ones) of the functions;
+// qualified names are given as "pkg.Func"
+func IsCallTo(names ...string) StmtPredicate {
+	nameMap := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		nameMap[n] = struct{}{}
+	}
+
+	return func(stmt ast.Stmt) bool {
+		var call *ast.CallExpr
+
+		switch s := stmt.(type) {
+		case *ast.ExprStmt:
+			call, _ = s.X.(*ast.CallExpr)
+		case *ast.DeferStmt:
+			call = s.Call
+		case *ast.GoStmt:
+			call = s.Call
+		}
+
+		if call == nil {
+			return false
+		}
+
+		_, ok := nameMap[callName(call)]
+		return ok
+	}
+}
+
+// callName returns the name of a called function as "Func" or "pkg.Func";
+// calls of function values and methods of values declared in the file have no names
+func callName(call *ast.CallExpr) string {
+	switch fn := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		if fn.Obj == nil || fn.Obj.Kind == ast.Fun {
+			return fn.Name
+		}
+	case *ast.SelectorExpr:
+		if x, ok := fn.X.(*ast.Ident); ok && x.Obj == nil {
+			return x.Name + "." + fn.Sel.Name
+		}
+	}
+	return ""
+}
+
+// callName is like the callName function, but qualified names are given by names of imported packages instead of their aliases
+// in the file, e.g. "errors.New" of e.New() with `import e "errors"`; calls of local function values
+// and of selectors qualified by other names than imports have no names
+func (f *file) callName(call *ast.CallExpr) string {
+	switch fn := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		if fn.Obj == nil || f.ast.Scope != nil && f.ast.Scope.Lookup(fn.Name) == fn.Obj {
+			return fn.Name
+		}
+	case *ast.SelectorExpr:
+		x, ok := fn.X.(*ast.Ident)
+		if !ok || x.Obj != nil {
+			return ""
+		}
+
+		if path := f.importPath(x.Name); path != "" {
+			return pathPkgName(path) + "." + fn.Sel.Name
+		}
+	}
+	return ""
+}
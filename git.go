@@ -0,0 +1,59 @@
+package goparser
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ContentProvider returns the content of a file at a revision
+type ContentProvider func(revision, filePath string) ([]byte, error)
+
+// NewFromGit returns a new instance of GoParser for the file at the revision (a commit, a branch, a tag)
+// of the git repository; filePath is relative to the repository root and is used as the path of the file,
+// so values of different revisions match in Diff:
+//
+//	old, err := NewFromGit(".", "v1.2.0", "config/defaults.go")
+//	...
+//	changes := DiffParsers(old, cur, "config")
+func NewFromGit(repoPath, revision, filePath string, opts ...Option) (*GoParser, error) {
+	return NewFromRevision(GitContent(repoPath), revision, filePath, opts...)
+}
+
+// NewFromRevision returns a new instance of GoParser for the file at the revision got from the content provider,
+// e.g. to read files from a VCS other than git
+func NewFromRevision(content ContentProvider, revision, filePath string, opts ...Option) (*GoParser, error) {
+	filePath = filepath.Clean(filePath)
+
+	src, err := content(revision, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return newInMemory(filePath, src, newOptions(opts))
+}
+
+// GitContent returns a ContentProvider reading files of the repository with `git show <revision>:<path>`
+func GitContent(repoPath string) ContentProvider {
+	return func(revision, filePath string) ([]byte, error) {
+		if revision == "" || strings.HasPrefix(revision, "-") {
+			return nil, fmt.Errorf("invalid revision %q", revision)
+		}
+
+		object := revision + ":" + path.Clean(filepath.ToSlash(filePath))
+
+		out, err := exec.Command("git", "-C", repoPath, "show", object).Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+				return nil, fmt.Errorf("git show %s: %s", object, strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return nil, fmt.Errorf("git show %s: %w", object, err)
+		}
+
+		return out, nil
+	}
+}
@@ -1,18 +1,23 @@
 package goparser
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
+	"io/fs"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
-	"strings"
 )
 
 // represents integer types
 type iInt interface {
-	uint8 | uint16 | uint32 | uint64 | int8 | int16 | int32 | int64
+	uint8 | uint16 | uint32 | uint64 | int8 | int16 | int32 | int64 | int | uint | uintptr
 }
 
 // represents float types
@@ -25,25 +30,145 @@ type iLit interface {
 	string | iInt | iFloat | bool
 }
 
+// DeclInfo contains common info about a labeled declaration
+type DeclInfo struct {
+	// Doc is the first matched label
+	//
+	// Deprecated: use Label, Doc is kept for compatibility and equals Label.
+	Doc string
+
+	// Label is the first matched label
+	Label string
+
+	// Labels contains all matched labels
+	Labels []string
+
+	// Description is the text of the doc comment without matched label lines,
+	// e.g. "Timeout of requests." for
+	//
+	//	// parser
+	//	// Timeout of requests.
+	//	var timeout = 30
+	Description string
+
+	// Args contains key=value arguments following matched labels, e.g. `// parser owner=team-a`, `// parser:env=prod`
+	// or `// parser(name="max_conns", secret=true)`
+	Args map[string]string
+
+	Name string
+
+	// Type is the declared type written as in the source, e.g. "int64" or "time.Duration", or the type of the conversion
+	// the value is written as if it's omitted, e.g. "float32" of `var x = float32(3.14)` (see unconvert);
+	// constants without values inherit the type of the previous spec of a const block
+	Type string
+
+	// Func is the name of the enclosing function of a declaration in a function body ("Type.Method" for methods),
+	// see WithFuncBodies
+	Func string
+
+	// Package is the import path of the declaring package, it's set with WithModule or by NewFromPackages
+	Package string
+
+	// Pos is the position of the declared name, it identifies the declaration
+	Pos token.Position
+
+	// LabelPos is the position of the first matched label comment
+	LabelPos token.Position
+
+	// Usage is the position of the first reference to the name in its package,
+	// references in Example functions are preferred; it's set with WithUsages only
+	Usage token.Position
+
+	// Truncated is set when some elements of a composite value are omitted because of limits
+	// set with WithMaxDepth or WithMaxElements
+	Truncated bool
+
+	// DocTruncated is set when some doc comment lines aren't scanned for labels because of limits
+	// set with WithMaxLabelLength or WithMaxDocLines
+	DocTruncated bool
+
+	// Checksum is a hex-encoded sha256 of the doc comment, the name and the value source text;
+	// it changes whenever any of them changes
+	Checksum string
+}
+
+func (d *DeclInfo) declInfo() *DeclInfo {
+	return d
+}
+
+// Dedupe merges values of the same declaration found by different calls (e.g. with different labels)
+// into the first one, aggregating their labels
+//
+//	values := Dedupe(GetBasicValues[string](p, "parser"), GetBasicValues[string](p, "parser:str"))
+func Dedupe[T any, P interface {
+	*T
+	declInfo() *DeclInfo
+}](values ...[]T) []T {
+	result := make([]T, 0)
+	index := make(map[token.Position]int)
+
+	for _, vs := range values {
+		for _, v := range vs {
+			info := P(&v).declInfo()
+
+			i, ok := index[info.Pos]
+			if !ok || !info.Pos.IsValid() {
+				index[info.Pos] = len(result)
+				result = append(result, v)
+				continue
+			}
+
+			first := P(&result[i]).declInfo()
+			first.Labels = mergeLabels(first.Labels, info.Labels)
+		}
+	}
+
+	return result
+}
+
+func mergeLabels(labels, other []string) []string {
+	result := append([]string(nil), labels...)
+
+outer:
+	for _, l := range other {
+		for _, r := range result {
+			if r == l {
+				continue outer
+			}
+		}
+		result = append(result, l)
+	}
+
+	return result
+}
+
 // LitValue contains basic literal value
 type LitValue[V iLit] struct {
-	Doc   string
-	Name  string
+	DeclInfo
 	Value V
+
+	// Format describes how an integer literal is written, zero for other literals
+	Format NumFormat
 }
 
-// SliceLitValue contains a slice of basic literal values
-type SliceLitValue[V iLit] struct {
-	Doc   string
-	Name  string
+// SliceLitValue contains a slice of basic literal values, elements of SliceLitValue[any] have types
+// inferred from literals (see GetAnySliceValues)
+type SliceLitValue[V any] struct {
+	DeclInfo
 	Value []V
+
+	// Len is the declared length of an array literal, e.g. 3 for `[3]int{1}` or 2 for `[...]string{"a", "b"}`;
+	// it's 0 for slices and arrays of non-constant lengths
+	Len int
 }
 
 // MapLitValue contains a map with basic literal values as keys and values
 type MapLitValue[K, V iLit] struct {
-	Doc   string
-	Name  string
+	DeclInfo
 	Value map[K]V
+
+	// Formats describes how integer literal values are written by their keys, see LitValue.Format
+	Formats map[K]NumFormat
 }
 
 // LitVal represents a basic response type for walk callback function
@@ -51,14 +176,98 @@ type LitVal[K, V iLit] interface {
 	LitValue[V] | SliceLitValue[V] | MapLitValue[K, V]
 }
 
-// GoParser contains an instance of ast.File
+// GoParser contains parsed Go files
 type GoParser struct {
-	f *ast.File
+	files []*file
+	opts  *options
+	types *typeInfo
+	edits map[*file][]edit
+
+	usages map[string]usageIndex
+}
+
+// file contains a parsed Go file and its source
+type file struct {
+	fset *token.FileSet
+	path string
+	ast  *ast.File
+	src  []byte
+
+	// inMemory is set for source that wasn't read from the path, it can't be written back
+	inMemory bool
+
+	// pkgPath is the import path of the package, it's set in module-aware mode or by NewFromPackages
+	pkgPath string
 }
 
 // New returns a new instance of GoParser
-func New(path string) (*GoParser, error) {
-	stat, err := os.Stat(path)
+func New(path string, opts ...Option) (*GoParser, error) {
+	o := newOptions(opts)
+
+	if _, ok := o.overlay[overlayKey(path)]; !ok {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if stat.IsDir() {
+			return nil, fmt.Errorf("%q is a directory", path)
+		}
+	}
+
+	src, err := o.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := newFromSource(path, src, o)
+	if err != nil {
+		return nil, err
+	}
+
+	g.setPackagePaths()
+
+	return g, nil
+}
+
+// NewFromSource returns a new instance of GoParser for in-memory Go source;
+// the name is used as the file path in positions and errors
+//
+//	p, err := NewFromSource("config.go", []byte("package config ..."))
+func NewFromSource(name string, src []byte, opts ...Option) (*GoParser, error) {
+	return newInMemory(name, src, newOptions(opts))
+}
+
+// NewFromReader returns a new instance of GoParser for Go source read from r, e.g. fetched over the network
+func NewFromReader(r io.Reader, opts ...Option) (*GoParser, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return newInMemory(readerName, src, newOptions(opts))
+}
+
+func newInMemory(name string, src []byte, o *options) (*GoParser, error) {
+	g, err := newFromSource(name, src, o)
+	if err != nil {
+		return nil, err
+	}
+
+	g.files[0].inMemory = true
+
+	return g, nil
+}
+
+// NewFromFS returns a new instance of GoParser for the file of the file system, e.g. embed.FS;
+// the path is slash-separated as in fs.FS
+//
+//	//go:embed config/defaults.go
+//	var configFS embed.FS
+//
+//	p, err := NewFromFS(configFS, "config/defaults.go")
+func NewFromFS(fsys fs.FS, path string, opts ...Option) (*GoParser, error) {
+	stat, err := fs.Stat(fsys, path)
 	if err != nil {
 		return nil, err
 	}
@@ -67,12 +276,42 @@ func New(path string) (*GoParser, error) {
 		return nil, fmt.Errorf("%q is a directory", path)
 	}
 
-	fileAst, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	src, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newInMemory(path, src, newOptions(opts))
+}
+
+// readerName is the file name of source read by NewFromReader
+const readerName = "source.go"
+
+func newFromSource(path string, src []byte, o *options) (*GoParser, error) {
+	f, err := parseFile(token.NewFileSet(), path, src, o)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &GoParser{files: []*file{f}, opts: o}
+	if o.types {
+		g.checkTypes()
+	}
+
+	return g, nil
+}
+
+func parseFile(fset *token.FileSet, path string, src []byte, o *options) (*file, error) {
+	if o.preprocess != nil {
+		src = o.preprocess(path, src)
+	}
+
+	fileAst, err := parser.ParseFile(fset, path, src, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
 
-	return &GoParser{f: fileAst}, nil
+	return &file{fset: fset, path: path, ast: fileAst, src: src}, nil
 }
 
 // GetBasicValues returns a list of values containing literal values by godoc label
@@ -80,7 +319,7 @@ func New(path string) (*GoParser, error) {
 //    // someLabel
 //    var testVar = "3"
 func GetBasicValues[V iLit](g *GoParser, docLabels ...string) []LitValue[V] {
-	if len(docLabels) == 0 {
+	if g.noLabels(docLabels) {
 		return nil
 	}
 
@@ -89,21 +328,35 @@ func GetBasicValues[V iLit](g *GoParser, docLabels ...string) []LitValue[V] {
 		docMap[doc] = struct{}{}
 	}
 
-	return getBasicValues[V](g.f, docMap)
+	return getBasicValues[V](newResults[LitValue[V]](g), g, docMap)
+}
+
+func getBasicValues[V iLit](dst []LitValue[V], g *GoParser, docMap map[string]struct{}) []LitValue[V] {
+	return appendDecls(dst, g, docMap, basicValue[V](g))
 }
 
-func getBasicValues[V iLit](f *ast.File, docMap map[string]struct{}) []LitValue[V] {
-	return walkDecls[int64, V, LitValue[V]](f, docMap, func(doc, name string, val ast.Expr) *LitValue[V] {
+// basicValue returns a function converting a labeled value to LitValue, it returns nil for other values
+func basicValue[V iLit](g *GoParser) func(info DeclInfo, val ast.Expr) *LitValue[V] {
+	return func(info DeclInfo, val ast.Expr) *LitValue[V] {
 		var tVal V
+		var format NumFormat
 		_, isBool := (interface{})(tVal).(bool)
 
+		if lit, ok := bytesLit(val); ok {
+			val = lit
+		} else if lit, ok := foldStrings(val); ok {
+			val = lit
+		} else if lit, ok := basicLit(val); ok {
+			val = lit
+		}
+
 		switch v := val.(type) {
 		case *ast.Ident:
 			if !isBool {
 				return nil
 			}
 
-			b, ok := parseBool(v)
+			b, ok := boolIdent(v)
 			if !ok {
 				return nil
 			}
@@ -116,18 +369,39 @@ func getBasicValues[V iLit](f *ast.File, docMap map[string]struct{}) []LitValue[
 			}
 
 			tVal = *b
+
+			if v.Kind == token.INT {
+				format = intFormat(v.Value)
+			}
 		default:
 			return nil
 		}
 
 		lVal := &LitValue[V]{
-			Doc:   doc,
-			Name:  name,
-			Value: tVal,
+			DeclInfo: info,
+			Value:    tVal,
+			Format:   format,
 		}
 
 		return lVal
-	})
+	}
+}
+
+// AppendBasicValues is like GetBasicValues, but appends the values to dst and returns the extended slice,
+// so a slice can be reused between extractions
+//
+//	values = AppendBasicValues(values[:0], p, "someLabel")
+func AppendBasicValues[V iLit](dst []LitValue[V], g *GoParser, docLabels ...string) []LitValue[V] {
+	if g.noLabels(docLabels) {
+		return dst
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return getBasicValues[V](dst, g, docMap)
 }
 
 // GetSliceValues returns a list of values containing slices of literal values by godoc label
@@ -135,7 +409,7 @@ func getBasicValues[V iLit](f *ast.File, docMap map[string]struct{}) []LitValue[
 //    // someLabel
 //    var testVar = []string{"3"}
 func GetSliceValues[V iLit](g *GoParser, docLabels ...string) []SliceLitValue[V] {
-	if len(docLabels) == 0 {
+	if g.noLabels(docLabels) {
 		return nil
 	}
 
@@ -144,24 +418,44 @@ func GetSliceValues[V iLit](g *GoParser, docLabels ...string) []SliceLitValue[V]
 		docMap[doc] = struct{}{}
 	}
 
-	return getSliceValues[V](g.f, docMap)
+	return getSliceValues[V](newResults[SliceLitValue[V]](g), g, docMap)
 }
 
-func getSliceValues[V iLit](f *ast.File, docMap map[string]struct{}) []SliceLitValue[V] {
-	return walkDecls[int64, V, SliceLitValue[V]](f, docMap, func(doc, name string, val ast.Expr) *SliceLitValue[V] {
+func getSliceValues[V iLit](dst []SliceLitValue[V], g *GoParser, docMap map[string]struct{}) []SliceLitValue[V] {
+	return appendDecls(dst, g, docMap, sliceValue[V](g))
+}
+
+// sliceValue returns a function converting a labeled value to SliceLitValue, it returns nil for other values
+func sliceValue[V iLit](g *GoParser) func(info DeclInfo, val ast.Expr) *SliceLitValue[V] {
+	return func(info DeclInfo, val ast.Expr) *SliceLitValue[V] {
+		if lit, ok := bytesLit(val); ok {
+			return bytesValue[V](info, lit)
+		}
+
 		cmpVal, ok := val.(*ast.CompositeLit)
 		if !ok {
 			return nil
 		}
 
-		sValues := make([]V, 0, len(cmpVal.Elts))
-		for _, elt := range cmpVal.Elts {
-			bVal, ok := elt.(*ast.BasicLit)
+		lim := g.opts.newLimiter()
+		elts := lim.limit(cmpVal.Elts)
+		info.Truncated = lim.isTruncated()
+
+		if sValues, ok := parseBasicLits[V](elts); ok && len(sValues) > 0 {
+			return &SliceLitValue[V]{
+				DeclInfo: info,
+				Value:    sValues,
+				Len:      arrayLen(cmpVal),
+			}
+		}
+
+		sValues := make([]V, 0, len(elts))
+		for _, elt := range elts {
+			pVal, ok := parseElt[V](elt)
 			if !ok {
 				continue
 			}
 
-			pVal := parseBasicLit[V](bVal)
 			if pVal == nil {
 				return nil
 			}
@@ -175,14 +469,120 @@ func getSliceValues[V iLit](f *ast.File, docMap map[string]struct{}) []SliceLitV
 
 		if len(sValues) > 0 {
 			return &SliceLitValue[V]{
-				Doc:   doc,
-				Name:  name,
-				Value: sValues,
+				DeclInfo: info,
+				Value:    sValues,
+				Len:      arrayLen(cmpVal),
 			}
 		}
 
 		return nil
-	})
+	}
+}
+
+// bytesValue returns the string literal converted to []byte as SliceLitValue[byte], nil for other element types
+func bytesValue[V iLit](info DeclInfo, lit *ast.BasicLit) *SliceLitValue[V] {
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil || s == "" {
+		return nil
+	}
+
+	values, ok := (interface{})([]byte(s)).([]V)
+	if !ok {
+		return nil
+	}
+
+	return &SliceLitValue[V]{
+		DeclInfo: info,
+		Value:    values,
+	}
+}
+
+// bytesLit returns the string literal of a conversion to []byte, e.g. "secret" of `[]byte("secret")`;
+// concatenated literals are folded
+func bytesLit(val ast.Expr) (*ast.BasicLit, bool) {
+	call, ok := bytesCall(val)
+	if !ok {
+		return nil, false
+	}
+
+	return foldStrings(call.Args[0])
+}
+
+// bytesCall returns a conversion to []byte or []uint8
+func bytesCall(val ast.Expr) (*ast.CallExpr, bool) {
+	call, ok := ast.Unparen(val).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || call.Ellipsis.IsValid() {
+		return nil, false
+	}
+
+	arr, ok := ast.Unparen(call.Fun).(*ast.ArrayType)
+	if !ok || arr.Len != nil {
+		return nil, false
+	}
+
+	id, ok := arr.Elt.(*ast.Ident)
+
+	return call, ok && id.Obj == nil && (id.Name == "byte" || id.Name == "uint8")
+}
+
+// arrayLen returns the length of an array literal: the declared one or, for `[...]T{}`, the number of elements
+// taking indices of keyed elements into account; 0 for slices and lengths that aren't integer literals
+func arrayLen(lit *ast.CompositeLit) int {
+	t, ok := lit.Type.(*ast.ArrayType)
+	if !ok || t.Len == nil {
+		return 0
+	}
+
+	if _, ok := t.Len.(*ast.Ellipsis); !ok {
+		n, ok := intLit(t.Len)
+		if !ok {
+			return 0
+		}
+		return n
+	}
+
+	n, i := 0, 0
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if i, ok = intLit(kv.Key); !ok {
+				return 0
+			}
+		}
+
+		i++
+		n = max(n, i)
+	}
+
+	return n
+}
+
+// intLit returns the value of a non-negative integer literal
+func intLit(expr ast.Expr) (int, bool) {
+	lit, ok := basicLit(expr)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(lit.Value, 0, strconv.IntSize)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+
+	return int(n), true
+}
+
+// AppendSliceValues is like GetSliceValues, but appends the values to dst and returns the extended slice
+func AppendSliceValues[V iLit](dst []SliceLitValue[V], g *GoParser, docLabels ...string) []SliceLitValue[V] {
+	if g.noLabels(docLabels) {
+		return dst
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return getSliceValues[V](dst, g, docMap)
 }
 
 // GetMapValues returns a list of values containing maps with literal types as keys and values by godoc label
@@ -190,7 +590,7 @@ func getSliceValues[V iLit](f *ast.File, docMap map[string]struct{}) []SliceLitV
 //    // someLabel
 //    var testVar = map[int]string{3: "3"}
 func GetMapValues[K, V iLit](g *GoParser, docLabels ...string) []MapLitValue[K, V] {
-	if len(docLabels) == 0 {
+	if g.noLabels(docLabels) {
 		return nil
 	}
 
@@ -199,18 +599,48 @@ func GetMapValues[K, V iLit](g *GoParser, docLabels ...string) []MapLitValue[K,
 		docMap[doc] = struct{}{}
 	}
 
-	return getMapValues[K, V](g.f, docMap)
+	return getMapValues[K, V](newResults[MapLitValue[K, V]](g), g, docMap)
 }
 
-func getMapValues[K, V iLit](f *ast.File, docMap map[string]struct{}) []MapLitValue[K, V] {
-	return walkDecls[K, V, MapLitValue[K, V]](f, docMap, func(doc, name string, val ast.Expr) *MapLitValue[K, V] {
+func getMapValues[K, V iLit](dst []MapLitValue[K, V], g *GoParser, docMap map[string]struct{}) []MapLitValue[K, V] {
+	return appendDecls(dst, g, docMap, mapValue[K, V](g))
+}
+
+// mapValue returns a function converting a labeled value to MapLitValue, it returns nil for other values
+func mapValue[K, V iLit](g *GoParser) func(info DeclInfo, val ast.Expr) *MapLitValue[K, V] {
+	return func(info DeclInfo, val ast.Expr) *MapLitValue[K, V] {
 		cmpVal, ok := val.(*ast.CompositeLit)
 		if !ok {
 			return nil
 		}
 
-		cValues := make(map[K]V, len(cmpVal.Elts))
-		for _, elt := range cmpVal.Elts {
+		lim := g.opts.newLimiter()
+		elts := lim.limit(cmpVal.Elts)
+		info.Truncated = lim.isTruncated()
+
+		cValues := make(map[K]V, len(elts))
+		formats := make(map[K]NumFormat)
+
+		if keys, values, ok := splitKeyValues(elts); ok && len(keys) > 0 {
+			ks, kOk := parseBasicLits[K](keys)
+			vs, vOk := parseBasicLits[V](values)
+			if kOk && vOk {
+				for i, k := range ks {
+					cValues[k] = vs[i]
+					if format, ok := litFormat(values[i]); ok {
+						formats[k] = format
+					}
+				}
+
+				return &MapLitValue[K, V]{
+					DeclInfo: info,
+					Value:    cValues,
+					Formats:  formats,
+				}
+			}
+		}
+
+		for _, elt := range elts {
 			cVal, ok := elt.(*ast.KeyValueExpr)
 			if !ok {
 				continue
@@ -219,166 +649,520 @@ func getMapValues[K, V iLit](f *ast.File, docMap map[string]struct{}) []MapLitVa
 			keyVal := cVal.Key
 			valVal := cVal.Value
 
-			bKey, keyOk := keyVal.(*ast.BasicLit)
-			bVal, valOk := valVal.(*ast.BasicLit)
-			if !keyOk || !valOk {
-				continue
-			}
-
-			k := parseBasicLit[K](bKey)
-			v := parseBasicLit[V](bVal)
-			if k == nil || v == nil {
+			k, keyOk := parseElt[K](keyVal)
+			v, valOk := parseElt[V](valVal)
+			if !keyOk || !valOk || k == nil || v == nil {
 				continue
 			}
 
 			cValues[*k] = *v
+			if format, ok := litFormat(valVal); ok {
+				formats[*k] = format
+			}
 		}
 
 		if len(cValues) > 0 {
 			return &MapLitValue[K, V]{
-				Doc:   doc,
-				Name:  name,
-				Value: cValues,
+				DeclInfo: info,
+				Value:    cValues,
+				Formats:  formats,
 			}
 		}
 
 		return nil
+	}
+}
+
+// AppendMapValues is like GetMapValues, but appends the values to dst and returns the extended slice
+func AppendMapValues[K, V iLit](dst []MapLitValue[K, V], g *GoParser, docLabels ...string) []MapLitValue[K, V] {
+	if g.noLabels(docLabels) {
+		return dst
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return getMapValues[K, V](dst, g, docMap)
+}
+
+// newResults returns an empty result slice with the capacity set by WithResultCapacity
+func newResults[T any](g *GoParser) []T {
+	return make([]T, 0, g.opts.resultCap)
+}
+
+func walkDecls[T any](g *GoParser, docMap map[string]struct{}, fn func(info DeclInfo, val ast.Expr) *T) []T {
+	return appendDecls(newResults[T](g), g, docMap, fn)
+}
+
+// appendDecls appends results of fn for labeled declarations to dst
+func appendDecls[T any](dst []T, g *GoParser, docMap map[string]struct{}, fn func(info DeclInfo, val ast.Expr) *T) []T {
+	return appendDeclsFunc(dst, g, docMap, func(dst []T, _ *file, info DeclInfo, val ast.Expr) []T {
+		if res := fn(info, val); res != nil {
+			dst = append(dst, *res)
+		}
+		return dst
 	})
 }
 
-func walkDecls[K, V iLit, T LitVal[K, V]](f *ast.File, docMap map[string]struct{}, fn func(doc, name string, val ast.Expr) *T) []T {
-	result := make([]T, 0)
+// appendDeclsFunc is like appendDecls, but fn appends any number of results itself and gets the file of the declaration
+func appendDeclsFunc[T any](dst []T, g *GoParser, docMap map[string]struct{}, fn func(dst []T, f *file, info DeclInfo, val ast.Expr) []T) []T {
+	result := dst
+
+	g.walkLabeled(docMap, func(f *file, info DeclInfo, val ast.Expr) {
+		result = fn(result, f, info, val)
+	}, nil)
 
-	for _, d := range f.Decls {
-		switch decl := d.(type) {
-		case *ast.GenDecl:
-			for _, spec := range decl.Specs {
-				switch s := spec.(type) {
-				case *ast.ValueSpec:
-					for _, n := range s.Names {
-						if n.Obj == nil {
+	return result
+}
+
+// walkLabeled calls visit for labeled values of all files and skip (if it's not nil) for labeled declarations
+// without a value of their own with the diagnostic kind and the reason
+func (g *GoParser) walkLabeled(docMap map[string]struct{}, visit func(f *file, info DeclInfo, val ast.Expr), skip func(info DeclInfo, kind, reason string)) {
+	labels := newLabelMatcher(docMap, g.opts.labelRegexps)
+
+	for _, f := range g.files {
+		fVisit := func(info DeclInfo, val ast.Expr) {
+			visit(f, info, val)
+		}
+
+		for _, d := range f.ast.Decls {
+			switch decl := d.(type) {
+			case *ast.GenDecl:
+				g.walkGenDecl(f, decl, "", labels, fVisit, skip)
+			case *ast.FuncDecl:
+				if !g.opts.funcBodies || decl.Body == nil {
+					continue
+				}
+
+				name := funcName(decl)
+				ast.Inspect(decl.Body, func(n ast.Node) bool {
+					if ds, ok := n.(*ast.DeclStmt); ok {
+						g.walkGenDecl(f, ds.Decl.(*ast.GenDecl), name, labels, fVisit, skip)
+					}
+					return true
+				})
+			}
+		}
+	}
+}
+
+// walkGenDecl calls visit for labeled values of the declaration, each name is paired with its own value;
+// funcName is the name of the enclosing function of a declaration in a function body, see walkLabeled for skip
+func (g *GoParser) walkGenDecl(f *file, decl *ast.GenDecl, funcName string, docLabels *labelMatcher,
+	visit func(info DeclInfo, val ast.Expr), skip func(info DeclInfo, kind, reason string)) {
+	var prevValues []ast.Expr
+	var prevType ast.Expr
+
+	// run contains the doc comment inherited by specs of a run without blank lines, see DocRun
+	var run *ast.CommentGroup
+	prevEnd := 0
+
+	for i, spec := range decl.Specs {
+		switch s := spec.(type) {
+		case *ast.ValueSpec:
+			iota := -1
+			if decl.Tok == token.CONST {
+				iota = i
+			}
+
+			values, typ := s.Values, s.Type
+			if len(values) == 0 && decl.Tok == token.CONST {
+				// implicit repetition of the previous expression list
+				values, typ = prevValues, prevType
+			}
+			prevValues, prevType = values, typ
+
+			if g.opts.docPolicy&DocRun != 0 {
+				if s.Doc != nil {
+					run = s.Doc
+				} else if f.fset.Position(s.Pos()).Line > prevEnd+1 {
+					run = nil
+				}
+				prevEnd = f.fset.Position(s.End()).Line
+			}
+
+			for j, n := range s.Names {
+				if n.Obj == nil {
+					continue
+				}
+
+				vSpec, ok := n.Obj.Decl.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+
+				comments := g.opts.labelComments(decl, vSpec)
+				if vSpec.Doc == nil && run != nil {
+					comments = append([]*ast.CommentGroup{run}, comments...)
+				}
+				if len(comments) == 0 {
+					continue
+				}
+
+				var labels []string
+				var args map[string]string
+				var labelPos token.Pos
+				var prose []*ast.Comment
+				scanned, docTruncated := 0, false
+			scan:
+				for _, group := range comments {
+					for _, doc := range group.List {
+						if scanned++; g.opts.maxDocLines > 0 && scanned > g.opts.maxDocLines {
+							docTruncated = true
+							break scan
+						}
+
+						if g.opts.maxLabelLen > 0 && len(doc.Text) > g.opts.maxLabelLen {
+							docTruncated = true
+							prose = append(prose, doc)
 							continue
 						}
 
-						vSpec, ok := n.Obj.Decl.(*ast.ValueSpec)
-						if !ok {
+						docTxt := g.opts.labelText(doc.Text)
+
+						label, lArgs := docTxt, map[string]string(nil)
+						if !docLabels.match(label) {
+							label, lArgs = splitLabelArgs(docTxt)
+						}
+
+						if !docLabels.match(label) {
+							prose = append(prose, doc)
 							continue
 						}
 
-						if vSpec.Doc == nil || len(vSpec.Doc.List) < 1 {
+						if slices.Contains(labels, label) {
 							continue
 						}
 
-						var foundDoc string
-						for _, doc := range vSpec.Doc.List {
-							docTxt := strings.TrimLeft(doc.Text, "/ ")
-							if _, ok := docMap[docTxt]; ok {
-								foundDoc = docTxt
-								break
-							}
+						if len(labels) == 0 {
+							labelPos = doc.Pos()
 						}
 
-						if foundDoc == "" {
-							continue
+						labels = append(labels, label)
+						for k, v := range lArgs {
+							if args == nil {
+								args = make(map[string]string)
+							}
+							args[k] = v
 						}
+					}
+				}
+
+				if len(labels) == 0 {
+					if docTruncated && skip != nil {
+						// a label might be among lines that aren't scanned
+						info := DeclInfo{Name: n.Name, Func: funcName, Package: f.pkgPath, Pos: f.fset.Position(n.Pos()), DocTruncated: true}
+						skip(info, DiagDocTruncated, "doc comment isn't scanned completely")
+					}
+					continue
+				}
 
-						val := vSpec.Values[0]
+				declType := typ
+				if declType == nil && len(values) == len(s.Names) {
+					declType = f.convType(values[j])
+				}
+
+				info := DeclInfo{
+					Doc:          labels[0],
+					Label:        labels[0],
+					Labels:       labels,
+					Description:  descriptionText(prose),
+					Args:         args,
+					Name:         n.Name,
+					Func:         funcName,
+					Type:         exprString(declType),
+					Package:      f.pkgPath,
+					Pos:          f.fset.Position(n.Pos()),
+					LabelPos:     f.fset.Position(labelPos),
+					DocTruncated: docTruncated,
+				}
 
-						res := fn(foundDoc, n.Name, val)
-						if res != nil {
-							result = append(result, *res)
+				if len(values) != len(s.Names) {
+					if skip != nil {
+						switch {
+						case len(values) == 0:
+							skip(info, DiagNoValue, "no value")
+						case len(values) == 1:
+							skip(info, DiagMultiValue, "initialized with a multi-value expression "+string(f.source(values[0])))
+						default:
+							skip(info, DiagMultiValue, "the number of values doesn't match the number of names")
 						}
 					}
+					continue
+				}
+
+				val := f.resolve(values[j], g.opts.resolveDepth)
+				info.Checksum = f.checksum(commentsText(comments), n.Name, val)
+
+				if g.opts.usages && funcName == "" {
+					info.Usage = g.firstUsage(f, n)
 				}
+
+				visit(info, floatLit(typ, g.constLit(f, n, val, iota)))
 			}
 		}
 	}
+}
 
-	return result
+// checksum returns a hex-encoded sha256 of the doc, the name and the source text of the value
+func (f *file) checksum(doc, name string, val ast.Expr) string {
+	h := sha256.New()
+	h.Write([]byte(doc))
+	h.Write([]byte{0})
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write(f.source(val))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// source returns the source text of the node
+func (f *file) source(n ast.Node) []byte {
+	tf := f.fset.File(n.Pos())
+	if tf == nil {
+		return nil
+	}
+
+	start, end := tf.Offset(n.Pos()), tf.Offset(n.End())
+	if start < 0 || end > len(f.src) || start > end {
+		return nil
+	}
+
+	return f.src[start:end]
 }
 
 // GetFuncNames returns a list of function names by receiver type or param types
 func GetFuncNames(g *GoParser, recType string, paramTypes ...string) []string {
 	result := make([]string, 0)
 
-outer:
-	for _, d := range g.f.Decls {
-		switch decl := d.(type) {
-		case *ast.FuncDecl:
-			rec := decl.Recv
-			if rec == nil && recType != "" {
-				continue
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			if decl, ok := d.(*ast.FuncDecl); ok && funcMatches(decl, recType, paramTypes) {
+				result = append(result, decl.Name.Name)
 			}
+		}
+	}
 
-			if rec != nil {
-				r := rec.List[0]
-				switch rType := r.Type.(type) {
-				case *ast.Ident:
-					if rType.Name != recType {
-						continue
-					}
-				case *ast.StarExpr:
-					id, ok := rType.X.(*ast.Ident)
-					if !ok || id.Name != recType {
-						continue
-					}
-				}
-			}
+	return result
+}
 
-			t := decl.Type
-			if (t == nil || t.Params == nil) && len(paramTypes) > 0 {
-				continue
+// funcMatches reports whether the function has the receiver type and params of all the types
+func funcMatches(decl *ast.FuncDecl, recType string, paramTypes []string) bool {
+	rec := decl.Recv
+	if rec == nil && recType != "" {
+		return false
+	}
+
+	if rec != nil {
+		r := rec.List[0]
+		switch rType := r.Type.(type) {
+		case *ast.Ident:
+			if rType.Name != recType {
+				return false
 			}
+		case *ast.StarExpr:
+			id, ok := rType.X.(*ast.Ident)
+			if !ok || id.Name != recType {
+				return false
+			}
+		}
+	}
 
-			if t != nil && t.Params != nil {
-				paramsMap := make(map[string]struct{}, len(t.Params.List))
-
-				for _, par := range t.Params.List {
-					switch pType := par.Type.(type) {
-					case *ast.Ident:
-						paramsMap[pType.Name] = struct{}{}
-					case *ast.StarExpr:
-						switch sType := pType.X.(type) {
-						case *ast.Ident:
-							paramsMap[sType.Name] = struct{}{}
-						case *ast.SelectorExpr:
-							if sType.Sel == nil {
-								continue
-							}
-							paramsMap[sType.Sel.Name] = struct{}{}
-						default:
-							continue
-						}
-					case *ast.SelectorExpr:
-						if pType.Sel == nil {
-							continue
-						}
-						paramsMap[pType.Sel.Name] = struct{}{}
-					}
-				}
+	t := decl.Type
+	if (t == nil || t.Params == nil) && len(paramTypes) > 0 {
+		return false
+	}
 
-				for _, par := range paramTypes {
-					_, ok := paramsMap[par]
-					_, okQt := paramsMap[fmt.Sprintf("%q", par)]
-					if !ok && !okQt {
-						continue outer
-					}
-				}
+	if t != nil && t.Params != nil {
+		paramsMap := make(map[string]struct{}, len(t.Params.List))
+
+		for _, par := range t.Params.List {
+			if name := typeName(par.Type); name != "" {
+				paramsMap[name] = struct{}{}
+			}
+		}
+
+		for _, par := range paramTypes {
+			_, ok := paramsMap[par]
+			_, okQt := paramsMap[fmt.Sprintf("%q", par)]
+			if !ok && !okQt {
+				return false
 			}
+		}
+	}
+
+	return true
+}
 
-			result = append(result, decl.Name.Name)
+// GetFuncNamesByResults returns a list of names of functions and methods having results of all the types;
+// types are matched by name like param types of GetFuncNames; in typed mode "error" also matches
+// named types implementing the error interface, e.g. *ValidationError
+//
+//	names := GetFuncNamesByResults(p, "error") // all functions that can fail
+func GetFuncNamesByResults(g *GoParser, resultTypes ...string) []string {
+	result := make([]string, 0)
+
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			if decl, ok := d.(*ast.FuncDecl); ok && g.resultsMatch(decl, resultTypes) {
+				result = append(result, decl.Name.Name)
+			}
 		}
 	}
 
 	return result
 }
 
-func parseBool(val *ast.Ident) (result bool, ok bool) {
-	b, err := strconv.ParseBool(val.Name)
-	if err != nil {
+// resultsMatch reports whether the function has results of all the types
+func (g *GoParser) resultsMatch(decl *ast.FuncDecl, resultTypes []string) bool {
+	resultsMap := make(map[string]struct{})
+	if res := decl.Type.Results; res != nil {
+		for _, r := range res.List {
+			if name := typeName(r.Type); name != "" {
+				resultsMap[name] = struct{}{}
+			}
+			if g.isErrorType(r.Type) {
+				resultsMap["error"] = struct{}{}
+			}
+		}
+	}
+
+	for _, typ := range resultTypes {
+		if _, ok := resultsMap[typ]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// typeName returns the name of a named type or a pointer to it without the package qualifier, "" for other types
+func typeName(t ast.Expr) string {
+	switch tt := t.(type) {
+	case *ast.Ident:
+		return tt.Name
+	case *ast.StarExpr:
+		switch st := tt.X.(type) {
+		case *ast.Ident:
+			return st.Name
+		case *ast.SelectorExpr:
+			return st.Sel.Name
+		}
+	case *ast.SelectorExpr:
+		return tt.Sel.Name
+	}
+
+	return ""
+}
+
+// basicLit returns the expression as a basic literal; signed numeric literals, e.g. -30 or +1.5,
+// are returned as a single literal with the sign folded into the value, parentheses are unwrapped: (42), -(2)
+func basicLit(expr ast.Expr) (*ast.BasicLit, bool) {
+	switch v := ast.Unparen(expr).(type) {
+	case *ast.BasicLit:
+		return v, true
+	case *ast.UnaryExpr:
+		lit, ok := ast.Unparen(v.X).(*ast.BasicLit)
+		if !ok || (lit.Kind != token.INT && lit.Kind != token.FLOAT) {
+			return nil, false
+		}
+
+		switch v.Op {
+		case token.SUB:
+			return &ast.BasicLit{ValuePos: v.Pos(), Kind: lit.Kind, Value: "-" + lit.Value}, true
+		case token.ADD:
+			return &ast.BasicLit{ValuePos: v.Pos(), Kind: lit.Kind, Value: lit.Value}, true
+		}
+	}
+
+	return nil, false
+}
+
+// foldStrings folds a concatenation of string literals into a single literal
+//
+//	"line1\n" +
+//		"line2\n"
+func foldStrings(val ast.Expr) (*ast.BasicLit, bool) {
+	s, ok := concatStrings(val)
+	if !ok {
+		return nil, false
+	}
+
+	return &ast.BasicLit{ValuePos: val.Pos(), Kind: token.STRING, Value: strconv.Quote(s)}, true
+}
+
+func concatStrings(val ast.Expr) (string, bool) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		if v.Kind != token.STRING {
+			return "", false
+		}
+
+		s, err := strconv.Unquote(v.Value)
+		return s, err == nil
+	case *ast.ParenExpr:
+		return concatStrings(v.X)
+	case *ast.BinaryExpr:
+		if v.Op != token.ADD {
+			return "", false
+		}
+
+		x, ok := concatStrings(v.X)
+		if !ok {
+			return "", false
+		}
+
+		y, ok := concatStrings(v.Y)
+		if !ok {
+			return "", false
+		}
+
+		return x + y, true
+	}
+
+	return "", false
+}
+
+// parseElt parses an element of a composite literal: a basic literal or, for bool values, true or false;
+// ok is false if the element isn't a literal, the value is nil if it's a literal of another type
+func parseElt[V iLit](expr ast.Expr) (v *V, ok bool) {
+	if id, isIdent := ast.Unparen(expr).(*ast.Ident); isIdent {
+		b, ok := boolIdent(id)
+		if !ok {
+			return nil, false
+		}
+
+		if v, isBool := (interface{})(b).(V); isBool {
+			return &v, true
+		}
+
+		return nil, true
+	}
+
+	lit, ok := basicLit(expr)
+	if !ok {
+		return nil, false
+	}
+
+	return parseBasicLit[V](lit), true
+}
+
+// boolIdent returns the value of the predeclared true or false (not shadowed by a declaration of the file)
+func boolIdent(id *ast.Ident) (result bool, ok bool) {
+	if id.Obj != nil {
 		return false, false
 	}
-	return b, true
+
+	switch id.Name {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+
+	return false, false
 }
 
 func parseBasicLit[V iLit](val *ast.BasicLit) *V {
@@ -395,6 +1179,24 @@ func parseBasicLit[V iLit](val *ast.BasicLit) *V {
 		}
 
 		zeroVal = (interface{})(strVal).(V)
+	case int:
+		iv := parseIntLit[int](val)
+		if iv == nil {
+			return nil
+		}
+		zeroVal = (interface{})(*iv).(V)
+	case uint:
+		iv := parseIntLit[uint](val)
+		if iv == nil {
+			return nil
+		}
+		zeroVal = (interface{})(*iv).(V)
+	case uintptr:
+		iv := parseIntLit[uintptr](val)
+		if iv == nil {
+			return nil
+		}
+		zeroVal = (interface{})(*iv).(V)
 	case int8:
 		iv := parseIntLit[int8](val)
 		if iv == nil {
@@ -463,14 +1265,15 @@ func parseBasicLit[V iLit](val *ast.BasicLit) *V {
 }
 
 func parseIntLit[I iInt](val *ast.BasicLit) *I {
-	if val.Kind != token.INT {
+	s, ok := intLitValue(val)
+	if !ok {
 		return nil
 	}
 
 	var parsed I
 
 	parseI := func() bool {
-		v := parseInt[I](val.Value)
+		v := parseInt[I](s)
 		if v == nil {
 			return false
 		}
@@ -479,7 +1282,7 @@ func parseIntLit[I iInt](val *ast.BasicLit) *I {
 	}
 
 	parseU := func() bool {
-		v := parseUint[I](val.Value)
+		v := parseUint[I](s)
 		if v == nil {
 			return false
 		}
@@ -488,35 +1291,11 @@ func parseIntLit[I iInt](val *ast.BasicLit) *I {
 	}
 
 	switch (interface{})(parsed).(type) {
-	case int8:
-		if !parseI() {
-			return nil
-		}
-	case int16:
-		if !parseI() {
-			return nil
-		}
-	case int32:
+	case int, int8, int16, int32, int64:
 		if !parseI() {
 			return nil
 		}
-	case int64:
-		if !parseI() {
-			return nil
-		}
-	case uint8:
-		if !parseU() {
-			return nil
-		}
-	case uint16:
-		if !parseU() {
-			return nil
-		}
-	case uint32:
-		if !parseU() {
-			return nil
-		}
-	case uint64:
+	case uint, uint8, uint16, uint32, uint64, uintptr:
 		if !parseU() {
 			return nil
 		}
@@ -532,11 +1311,13 @@ func parseUint[I iInt](s string) *I {
 	var err error
 
 	parse := func(bitSize int) {
-		uintVal, err = strconv.ParseUint(s, 10, bitSize)
+		uintVal, err = strconv.ParseUint(s, 0, bitSize)
 	}
 
 	var zeroVal I
 	switch (interface{})(zeroVal).(type) {
+	case uint, uintptr:
+		parse(strconv.IntSize)
 	case uint8:
 		parse(8)
 	case uint16:
@@ -553,7 +1334,7 @@ func parseUint[I iInt](s string) *I {
 		return nil
 	}
 
-	zeroVal = (interface{})(uintVal).(I)
+	zeroVal = I(uintVal)
 
 	return &zeroVal
 }
@@ -563,11 +1344,13 @@ func parseInt[I iInt](s string) *I {
 	var err error
 
 	parse := func(bitSize int) {
-		intVal, err = strconv.ParseInt(s, 10, bitSize)
+		intVal, err = strconv.ParseInt(s, 0, bitSize)
 	}
 
 	var zeroVal I
 	switch (interface{})(zeroVal).(type) {
+	case int:
+		parse(strconv.IntSize)
 	case int8:
 		parse(8)
 	case int16:
@@ -584,7 +1367,7 @@ func parseInt[I iInt](s string) *I {
 		return nil
 	}
 
-	zeroVal = (interface{})(intVal).(I)
+	zeroVal = I(intVal)
 
 	return &zeroVal
 }
@@ -643,7 +1426,48 @@ func parseFloat[F iFloat](s string) *F {
 		return nil
 	}
 
-	zeroVal = (interface{})(fVal).(F)
+	zeroVal = F(fVal)
 
 	return &zeroVal
 }
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// intLitValue returns the value of an integer literal, rune literals (e.g. 'a' of `var b byte = 'a'`)
+// are converted to decimal integers
+func intLitValue(val *ast.BasicLit) (string, bool) {
+	switch val.Kind {
+	case token.INT:
+		return val.Value, true
+	case token.CHAR:
+		r, ok := parseChar(val)
+		return strconv.Itoa(int(r)), ok
+	}
+
+	return "", false
+}
+
+func parseChar(val *ast.BasicLit) (rune, bool) {
+	if val.Kind != token.CHAR {
+		return 0, false
+	}
+
+	s := val.Value
+	if len(s) < 3 {
+		return 0, false
+	}
+
+	r, _, tail, err := strconv.UnquoteChar(s[1:len(s)-1], '\'')
+	if err != nil || tail != "" {
+		return 0, false
+	}
+
+	return r, true
+}
@@ -0,0 +1,33 @@
+package goparser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestParser parses the source written to a file of a temporary directory
+func newTestParser(t testing.TB, src string, opts ...Option) *GoParser {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "src.go")
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := New(path, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+// basicValues returns values by names of declarations
+func basicValues[T iLit](values []LitValue[T]) map[string]T {
+	res := make(map[string]T, len(values))
+	for _, v := range values {
+		res[v.Name] = v.Value
+	}
+	return res
+}
@@ -0,0 +1,235 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// GetMethodsByInterface returns names ("Type.Method") of methods of types satisfying the interface with params
+// of all the types (see GetFuncNames), e.g. to discover plugins; the interface is declared in parsed files,
+// in typed mode it can also be one of an imported package given as "pkg.Name".
+// Without type information, a type satisfies the interface if it (or a pointer to it) has methods
+// with the same names and signatures written the same way; embedded interfaces of other packages can't be resolved,
+// such interfaces are never satisfied
+//
+//	names := GetMethodsByInterface(p, "Plugin") // e.g. ["GzipPlugin.Init" "GzipPlugin.Name"]
+func GetMethodsByInterface(g *GoParser, iface string, paramTypes ...string) []string {
+	var impl map[string]struct{}
+	if g.types != nil {
+		impl = g.typedImplementers(iface)
+	} else {
+		impl = g.implementers(iface)
+	}
+
+	result := make([]string, 0)
+
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Recv == nil {
+				continue
+			}
+
+			rec := recvTypeName(decl)
+			if _, ok = impl[rec]; ok && funcMatches(decl, rec, paramTypes) {
+				result = append(result, funcName(decl))
+			}
+		}
+	}
+
+	return result
+}
+
+// typedImplementers returns names of package-level types of parsed packages implementing the interface
+func (g *GoParser) typedImplementers(name string) map[string]struct{} {
+	it := g.lookupInterface(name)
+	if it == nil {
+		return nil
+	}
+
+	result := make(map[string]struct{})
+
+	for _, pkg := range g.types.pkgs {
+		if pkg == nil {
+			continue
+		}
+
+		scope := pkg.Scope()
+		for _, n := range scope.Names() {
+			tn, ok := scope.Lookup(n).(*types.TypeName)
+			if !ok || types.IsInterface(tn.Type()) {
+				continue
+			}
+
+			if types.Implements(tn.Type(), it) || types.Implements(types.NewPointer(tn.Type()), it) {
+				result[n] = struct{}{}
+			}
+		}
+	}
+
+	return result
+}
+
+// lookupInterface returns an interface declared in parsed packages or, if the name is qualified, in their imports
+func (g *GoParser) lookupInterface(name string) *types.Interface {
+	pkgName, typName, qualified := strings.Cut(name, ".")
+	if !qualified {
+		if tn := g.lookupType(name); tn != nil {
+			it, _ := tn.Type().Underlying().(*types.Interface)
+			return it
+		}
+		return nil
+	}
+
+	for _, pkg := range g.types.pkgs {
+		if pkg == nil {
+			continue
+		}
+
+		for _, imp := range pkg.Imports() {
+			if imp.Name() != pkgName {
+				continue
+			}
+
+			if tn, ok := imp.Scope().Lookup(typName).(*types.TypeName); ok {
+				it, _ := tn.Type().Underlying().(*types.Interface)
+				return it
+			}
+		}
+	}
+
+	return nil
+}
+
+// implementers returns names of types declared in parsed files with methods of the interface by their signatures
+func (g *GoParser) implementers(name string) map[string]struct{} {
+	want, ok := g.interfaceMethods(name, make(map[string]struct{}))
+	if !ok {
+		return nil
+	}
+
+	methods := make(map[string]map[string]string) // type: method: signature
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Recv == nil {
+				continue
+			}
+
+			rec := recvTypeName(decl)
+			if methods[rec] == nil {
+				methods[rec] = make(map[string]string)
+			}
+			methods[rec][decl.Name.Name] = funcSignature(decl.Type)
+		}
+	}
+
+	result := make(map[string]struct{})
+
+outer:
+	for typ, ms := range methods {
+		if typ == "" || typ == name {
+			continue
+		}
+
+		for m, sig := range want {
+			if ms[m] != sig {
+				continue outer
+			}
+		}
+
+		result[typ] = struct{}{}
+	}
+
+	return result
+}
+
+// interfaceMethods returns signatures of methods of an interface declared in parsed files by method names,
+// including methods of embedded interfaces; ok is false if the interface or an embedded one isn't found
+func (g *GoParser) interfaceMethods(name string, seen map[string]struct{}) (methods map[string]string, ok bool) {
+	if _, ok = seen[name]; ok {
+		return nil, false
+	}
+	seen[name] = struct{}{}
+
+	it := g.findInterface(name)
+	if it == nil {
+		return nil, false
+	}
+
+	methods = make(map[string]string)
+
+	for _, m := range it.Methods.List {
+		if ft, ok := m.Type.(*ast.FuncType); ok {
+			for _, n := range m.Names {
+				methods[n.Name] = funcSignature(ft)
+			}
+			continue
+		}
+
+		id, ok := m.Type.(*ast.Ident)
+		if !ok {
+			return nil, false
+		}
+
+		embedded, ok := g.interfaceMethods(id.Name, seen)
+		if !ok {
+			return nil, false
+		}
+
+		for n, sig := range embedded {
+			methods[n] = sig
+		}
+	}
+
+	return methods, true
+}
+
+// findInterface returns an interface type declared in parsed files by name
+func (g *GoParser) findInterface(name string) *ast.InterfaceType {
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+
+			for _, spec := range decl.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.Name.Name != name {
+					continue
+				}
+
+				if it, ok := ts.Type.(*ast.InterfaceType); ok {
+					return it
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// funcSignature returns types of params and results of the function written as in the source, without names
+func funcSignature(ft *ast.FuncType) string {
+	return "(" + fieldTypes(ft.Params) + ")(" + fieldTypes(ft.Results) + ")"
+}
+
+func fieldTypes(fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+
+	list := make([]string, 0, len(fields.List))
+	for _, field := range fields.List {
+		typ := types.ExprString(field.Type)
+
+		list = append(list, typ)
+		for i := 1; i < len(field.Names); i++ {
+			list = append(list, typ)
+		}
+	}
+
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,322 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SelectorRef is a textual reference to a qualified identifier, e.g. somepkg.SomeConst,
+// returned when the referenced value can't be resolved
+type SelectorRef string
+
+// AnyValue contains a basic literal value of a type inferred from the literal:
+// string, int64 (uint64 if it overflows int64), float64, rune, bool or SelectorRef
+type AnyValue struct {
+	DeclInfo
+	Value any
+
+	// Format describes how an integer literal is written, zero for other literals
+	Format NumFormat
+}
+
+// Result returns the value as Result
+func (v AnyValue) Result() Result {
+	if ref, ok := v.Value.(SelectorRef); ok {
+		return newResult(KindSelectorRef, v.DeclInfo, string(ref))
+	}
+
+	return withFormat(newResult(KindBasic, v.DeclInfo, v.Value), v.Format)
+}
+
+// InferValues returns a list of values containing literal values by godoc label
+// with types inferred from the literals, e.g. when types of labeled values aren't known in advance
+//
+//	// someLabel
+//	var testVar = 3 // int64(3)
+//
+//	// someLabel
+//	var testRef = time.Second // SelectorRef("time.Second")
+func InferValues(g *GoParser, docLabels ...string) []AnyValue {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	toAny := anyValue(g)
+
+	return appendDeclsFunc(newResults[AnyValue](g), g, docMap, func(dst []AnyValue, f *file, info DeclInfo, val ast.Expr) []AnyValue {
+		if v := toAny(f, info, val); v != nil {
+			dst = append(dst, *v)
+		}
+		return dst
+	})
+}
+
+// GetAnySliceValues returns a list of values containing slices and arrays of literals by godoc label,
+// each element has its own type inferred from the literal (see InferValues), e.g. of []interface{} literals;
+// elements that aren't literals are skipped
+//
+//	// someLabel
+//	var testVar = []interface{}{"a", 1, true} // []any{"a", int64(1), true}
+func GetAnySliceValues(g *GoParser, docLabels ...string) []SliceLitValue[any] {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return appendDeclsFunc(newResults[SliceLitValue[any]](g), g, docMap, func(dst []SliceLitValue[any], f *file, info DeclInfo, val ast.Expr) []SliceLitValue[any] {
+		lit, ok := val.(*ast.CompositeLit)
+		if !ok {
+			return dst
+		}
+
+		if _, ok := lit.Type.(*ast.ArrayType); !ok {
+			return dst
+		}
+
+		lim := g.opts.newLimiter()
+		elts := lim.limit(lit.Elts)
+		info.Truncated = lim.isTruncated()
+
+		values := make([]any, 0, len(elts))
+		for _, elt := range elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+
+			if v, ok := f.inferValue(elt); ok {
+				values = append(values, v)
+			}
+		}
+
+		if len(values) == 0 {
+			return dst
+		}
+
+		return append(dst, SliceLitValue[any]{
+			DeclInfo: info,
+			Value:    values,
+			Len:      arrayLen(lit),
+		})
+	})
+}
+
+// anyValue returns a function converting a labeled value to AnyValue typed by its declared type with WithDeclaredTypes
+func anyValue(g *GoParser) func(f *file, info DeclInfo, val ast.Expr) *AnyValue {
+	if !g.opts.declaredTypes {
+		return newAnyValue
+	}
+
+	return func(f *file, info DeclInfo, val ast.Expr) *AnyValue {
+		v := newAnyValue(f, info, val)
+		if v == nil {
+			return nil
+		}
+
+		if dv, ok := declaredValue(info.Type, v.Value); ok {
+			v.Value = dv
+			if isFloat(reflect.TypeOf(dv).Kind()) {
+				v.Format = NumFormat{} // integer literals of float values
+			}
+		}
+
+		return v
+	}
+}
+
+// declaredTypes contains predeclared basic types values can be converted to by declaredValue
+var declaredTypes = map[string]reflect.Type{
+	"bool":    reflect.TypeOf(false),
+	"string":  reflect.TypeOf(""),
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"rune":    reflect.TypeOf(rune(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"byte":    reflect.TypeOf(byte(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"uintptr": reflect.TypeOf(uintptr(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+}
+
+// declaredValue converts an inferred value to the declared type, ok is false for other types
+// and values that aren't representable by the type
+func declaredValue(typ string, v any) (any, bool) {
+	t, ok := declaredTypes[typ]
+	if !ok {
+		return nil, false
+	}
+
+	src := reflect.ValueOf(v)
+	dst := reflect.New(t).Elem()
+
+	switch {
+	case t.Kind() == src.Kind():
+		return v, true
+	case isSigned(src.Kind()):
+		i := src.Int()
+		switch {
+		case isSigned(t.Kind()) && !dst.OverflowInt(i):
+			dst.SetInt(i)
+		case isUnsigned(t.Kind()) && i >= 0 && !dst.OverflowUint(uint64(i)):
+			dst.SetUint(uint64(i))
+		case isFloat(t.Kind()):
+			dst.SetFloat(float64(i))
+		default:
+			return nil, false
+		}
+	case isUnsigned(src.Kind()):
+		u := src.Uint()
+		switch {
+		case isUnsigned(t.Kind()) && !dst.OverflowUint(u):
+			dst.SetUint(u)
+		case isFloat(t.Kind()):
+			dst.SetFloat(float64(u))
+		default:
+			return nil, false
+		}
+	case isFloat(src.Kind()) && isFloat(t.Kind()):
+		if dst.OverflowFloat(src.Float()) {
+			return nil, false
+		}
+		dst.SetFloat(src.Float())
+	default:
+		return nil, false
+	}
+
+	return dst.Interface(), true
+}
+
+func isSigned(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUnsigned(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// newAnyValue returns the value with the type inferred from the literal or nil if it isn't a literal
+func newAnyValue(f *file, info DeclInfo, val ast.Expr) *AnyValue {
+	v, ok := f.inferValue(val)
+	if !ok {
+		return nil
+	}
+
+	var format NumFormat
+	if lit, ok := basicLit(val); ok && lit.Kind == token.INT {
+		format = intFormat(lit.Value)
+	}
+
+	return &AnyValue{
+		DeclInfo: info,
+		Value:    v,
+		Format:   format,
+	}
+}
+
+// inferValue parses a basic literal or a bool constant into a value of the type inferred from the literal
+func (f *file) inferValue(val ast.Expr) (any, bool) {
+	if lit, ok := foldStrings(val); ok {
+		val = lit
+	} else if lit, ok := basicLit(val); ok {
+		val = lit
+	}
+
+	switch v := val.(type) {
+	case *ast.Ident:
+		return boolIdent(v)
+	case *ast.SelectorExpr:
+		return f.selectorRef(v)
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.STRING:
+			return basicAs[string](v)
+		case token.INT:
+			if i, ok := basicAs[int64](v); ok {
+				return i, true
+			}
+			return basicAs[uint64](v)
+		case token.FLOAT:
+			return basicAs[float64](v)
+		case token.CHAR:
+			return parseChar(v)
+		}
+	}
+
+	return nil, false
+}
+
+// selectorRef returns a reference to a package-qualified identifier; selectors of local values, e.g. cfg.Timeout,
+// aren't references
+func (f *file) selectorRef(sel *ast.SelectorExpr) (SelectorRef, bool) {
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Obj != nil || !f.isImportName(pkg.Name) {
+		return "", false
+	}
+
+	return SelectorRef(pkg.Name + "." + sel.Sel.Name), true
+}
+
+// isImportName reports whether the name is the name of a package imported by the file, see importPath
+func (f *file) isImportName(name string) bool {
+	return f.importPath(name) != ""
+}
+
+// importPath returns the path of the package imported by the file with the name or an empty string;
+// names of packages imported without a name are guessed by their paths, see pathPkgName
+func (f *file) importPath(name string) string {
+	for _, imp := range f.ast.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		if imp.Name != nil && imp.Name.Name == name || imp.Name == nil && pathPkgName(path) == name {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// pathPkgName returns the conventional name of the package by its import path:
+//
+//	net/http                     // http
+//	gopkg.in/yaml.v3             // yaml
+//	github.com/go-chi/chi/v5     // chi
+//	github.com/mattn/go-sqlite3  // sqlite3
+func pathPkgName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+
+	name, _, _ = strings.Cut(name, ".")
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+
+	return name
+}
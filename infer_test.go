@@ -0,0 +1,136 @@
+package goparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferValuesBoolIdents(t *testing.T) {
+	p := newTestParser(t, `package p
+
+// p
+var a = true
+
+// p
+var b = T
+
+// p
+var c = True
+
+// p
+var d = F
+
+// p
+var e = false
+
+// p
+var f = !true
+`)
+
+	tests := []struct {
+		name string
+		got  func() map[string]any
+		want map[string]any
+	}{
+		{
+			name: "InferValues",
+			got: func() map[string]any {
+				res := make(map[string]any)
+				for _, v := range InferValues(p, "p") {
+					res[v.Name] = v.Value
+				}
+				return res
+			},
+			want: map[string]any{"a": true, "e": false, "f": false},
+		},
+		{
+			name: "GetBasicValues",
+			got: func() map[string]any {
+				res := make(map[string]any)
+				for _, v := range GetBasicValues[bool](p, "p") {
+					res[v.Name] = v.Value
+				}
+				return res
+			},
+			want: map[string]any{"a": true, "e": false, "f": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferValuesSelectorRefs(t *testing.T) {
+	p := newTestParser(t, `package p
+
+import (
+	h "net/http"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type config struct{ Timeout int }
+
+var cfg config
+
+// p
+var a = time.Second
+
+// p
+var b = h.MethodGet
+
+// p
+var c = yaml.ScalarNode
+
+// p
+var d = cfg.Timeout
+
+// p
+var e = other.Value
+
+// p
+var f = http.MethodPost
+`)
+
+	got := make(map[string]any)
+	for _, v := range InferValues(p, "p") {
+		got[v.Name] = v.Value
+	}
+
+	want := map[string]any{
+		"a": SelectorRef("time.Second"),
+		"b": SelectorRef("h.MethodGet"),
+		"c": SelectorRef("yaml.ScalarNode"),
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPathPkgName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "time", want: "time"},
+		{path: "net/http", want: "http"},
+		{path: "gopkg.in/yaml.v3", want: "yaml"},
+		{path: "github.com/go-chi/chi/v5", want: "chi"},
+		{path: "github.com/mattn/go-sqlite3", want: "sqlite3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := pathPkgName(tt.path); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package goparser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// labelMatcher matches doc labels against labels given exactly, glob patterns and regexps (see WithLabelRegexps);
+// in patterns `*` matches any sequence of characters, `?` any single character and `[...]` a character class
+// (`[!...]` a negated one), e.g. "parser:*" matches "parser:db" and "parser:http"; patterns match labels
+// without whitespace only, so prose lines of doc comments aren't taken for labels
+type labelMatcher struct {
+	labels   map[string]struct{}
+	patterns []*regexp.Regexp
+	regexps  []*regexp.Regexp
+}
+
+func newLabelMatcher(labels map[string]struct{}, regexps []*regexp.Regexp) *labelMatcher {
+	m := &labelMatcher{labels: labels, regexps: regexps}
+
+	for l := range labels {
+		if re, ok := globRegexp(l); ok {
+			m.patterns = append(m.patterns, re)
+		}
+	}
+
+	return m
+}
+
+func (m *labelMatcher) match(label string) bool {
+	if _, ok := m.labels[label]; ok {
+		return true
+	}
+
+	for _, re := range m.regexps {
+		if re.MatchString(label) {
+			return true
+		}
+	}
+
+	if len(m.patterns) == 0 || label == "" || strings.ContainsFunc(label, unicode.IsSpace) {
+		return false
+	}
+
+	for _, re := range m.patterns {
+		if re.MatchString(label) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// noLabels reports whether there's nothing to match: no labels are given and no regexps are set with WithLabelRegexps
+func (g *GoParser) noLabels(docLabels []string) bool {
+	return len(docLabels) == 0 && len(g.opts.labelRegexps) == 0
+}
+
+// globRegexp returns an anchored regexp of the glob pattern, false if the label isn't a valid pattern
+func globRegexp(pattern string) (*regexp.Regexp, bool) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return nil, false
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end <= 0 {
+				return nil, false
+			}
+
+			class := pattern[i+1 : i+1+end]
+			if class[0] == '!' {
+				class = "^" + class[1:]
+			}
+
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+
+	return re, err == nil
+}
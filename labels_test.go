@@ -0,0 +1,176 @@
+package goparser
+
+import (
+	"reflect"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+const labelsSource = `package p
+
+// cfg
+var plain = 1
+
+// cfg:db
+var db = 2
+
+// cfg:http owner=team-a
+var http = 3
+
+// cfg:DB
+var upper = 4
+
+// @cfg
+var marked = 5
+
+// cfg is described here
+var prose = 6
+`
+
+func TestLabelMatching(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		labels []string
+		want   []string
+	}{
+		{name: "exact", labels: []string{"cfg"}, want: []string{"plain"}},
+		{name: "exact with args", labels: []string{"cfg:http"}, want: []string{"http"}},
+		{name: "glob", labels: []string{"cfg:*"}, want: []string{"db", "http", "upper"}},
+		{name: "glob class", labels: []string{"cfg:[a-z]*"}, want: []string{"db", "http"}},
+		{name: "glob single", labels: []string{"cfg?"}, want: nil},
+		{name: "markers", opts: []Option{WithLabelMarkers("@")}, labels: []string{"cfg"}, want: []string{"marked", "plain"}},
+		{
+			name: "regexp",
+			opts: []Option{WithLabelRegexps(regexp.MustCompile(`^cfg(:[a-z]+)?$`))},
+			want: []string{"db", "http", "plain"},
+		},
+		{
+			name:   "regexp and labels",
+			opts:   []Option{WithLabelRegexps(regexp.MustCompile(`^cfg:[a-z]+$`))},
+			labels: []string{"cfg:DB"},
+			want:   []string{"db", "http", "upper"},
+		},
+		{name: "no labels", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestParser(t, labelsSource, tt.opts...)
+
+			var got []string
+			for _, v := range GetBasicValues[int](p, tt.labels...) {
+				got = append(got, v.Name)
+			}
+			slices.Sort(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelArgs(t *testing.T) {
+	p := newTestParser(t, `package p
+
+// parser owner=team-a
+var trailing = 1
+
+// parser:env=prod name=maxConns
+var colon = 2
+
+// parser(name="max_conns", secret=true)
+var paren = 3
+`)
+
+	want := map[string]map[string]string{
+		"trailing": {"owner": "team-a"},
+		"colon":    {"env": "prod", "name": "maxConns"},
+		"paren":    {"name": "max_conns", "secret": "true"},
+	}
+
+	got := make(map[string]map[string]string)
+	for _, v := range GetBasicValues[int](p, "parser") {
+		if v.Label != "parser" {
+			t.Errorf("%s: got label %q", v.Name, v.Label)
+		}
+		got[v.Name] = v.Args
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSplitLabelArgs(t *testing.T) {
+	tests := []struct {
+		txt   string
+		label string
+		args  map[string]string
+	}{
+		{txt: "parser", label: "parser"},
+		{txt: "parser owner=team-a", label: "parser", args: map[string]string{"owner": "team-a"}},
+		{txt: "owner=team-a", label: "owner=team-a"},
+		{txt: "some prose", label: "some prose"},
+		{txt: "parser:env=prod", label: "parser", args: map[string]string{"env": "prod"}},
+		{txt: "parser:env=prod name=x", label: "parser", args: map[string]string{"env": "prod", "name": "x"}},
+		{txt: "cfg:db", label: "cfg:db"},
+		{txt: "cfg:db:env=dev", label: "cfg:db", args: map[string]string{"env": "dev"}},
+		{txt: ":env=dev", label: ":env=dev"},
+		{txt: "parser()", label: "parser"},
+		{txt: `parser(name="max_conns", secret=true)`, label: "parser", args: map[string]string{"name": "max_conns", "secret": "true"}},
+		{txt: `parser(note="a, b", flag, n = 3)`, label: "parser", args: map[string]string{"note": "a, b", "flag": "", "n": "3"}},
+		{txt: "parser(path=`C:\\dir`)", label: "parser", args: map[string]string{"path": `C:\dir`}},
+		{txt: `parser(name="x)`, label: `parser(name="x)`},
+		{txt: `parser(name="x" y)`, label: `parser(name="x" y)`},
+		{txt: "see f(x)", label: "see f(x)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.txt, func(t *testing.T) {
+			label, args := splitLabelArgs(tt.txt)
+			if label != tt.label || !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("got %q %v, want %q %v", label, args, tt.label, tt.args)
+			}
+		})
+	}
+}
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		ok      bool
+		match   []string
+		noMatch []string
+	}{
+		{pattern: "parser", ok: false},
+		{pattern: "parser:*", ok: true, match: []string{"parser:", "parser:db"}, noMatch: []string{"parser", "xparser:db"}},
+		{pattern: "cfg?", ok: true, match: []string{"cfg1"}, noMatch: []string{"cfg", "cfg12"}},
+		{pattern: "cfg[!0-9]", ok: true, match: []string{"cfgx"}, noMatch: []string{"cfg1"}},
+		{pattern: "a.b*", ok: true, match: []string{"a.bc"}, noMatch: []string{"axbc"}},
+		{pattern: "cfg[", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, ok := globRegexp(tt.pattern)
+			if ok != tt.ok {
+				t.Fatalf("got ok %v, want %v", ok, tt.ok)
+			}
+
+			for _, s := range tt.match {
+				if !re.MatchString(s) {
+					t.Errorf("%q doesn't match", s)
+				}
+			}
+
+			for _, s := range tt.noMatch {
+				if re.MatchString(s) {
+					t.Errorf("%q matches", s)
+				}
+			}
+		})
+	}
+}
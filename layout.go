@@ -0,0 +1,111 @@
+package goparser
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"runtime"
+	"sort"
+)
+
+// FieldLayout contains memory layout of a struct field
+type FieldLayout struct {
+	Name   string
+	Type   string
+	Offset int64
+	Size   int64
+	Align  int64
+
+	// Padding is the number of padding bytes after the field
+	Padding int64
+}
+
+// StructLayout contains memory layout of a struct for the current architecture
+type StructLayout struct {
+	Name   string
+	Size   int64
+	Align  int64
+	Fields []FieldLayout
+
+	// Padding is the total number of padding bytes
+	Padding int64
+
+	// Optimal is the suggested field order with minimal padding and OptimalSize is the struct size with that order
+	Optimal     []string
+	OptimalSize int64
+}
+
+// GetStructLayout returns memory layout of a struct type with the suggested field order; requires typed mode (see WithTypes)
+func GetStructLayout(g *GoParser, name string) (StructLayout, error) {
+	if g.types == nil {
+		return StructLayout{}, errors.New("struct layout requires typed mode, see WithTypes")
+	}
+
+	tn := g.lookupType(name)
+	if tn == nil {
+		return StructLayout{}, fmt.Errorf("type %q not found", name)
+	}
+
+	st, ok := tn.Type().Underlying().(*types.Struct)
+	if !ok {
+		return StructLayout{}, fmt.Errorf("type %q is not a struct", name)
+	}
+
+	fields := make([]*types.Var, st.NumFields())
+	for i := range fields {
+		fields[i] = st.Field(i)
+		if fields[i].Type() == types.Typ[types.Invalid] {
+			return StructLayout{}, fmt.Errorf("field %s of %q has invalid type", fields[i].Name(), name)
+		}
+	}
+
+	sizes := types.SizesFor("gc", runtime.GOARCH)
+	offsets := sizes.Offsetsof(fields)
+
+	result := StructLayout{
+		Name:   name,
+		Size:   sizes.Sizeof(st),
+		Align:  sizes.Alignof(st),
+		Fields: make([]FieldLayout, len(fields)),
+	}
+
+	for i, f := range fields {
+		fl := FieldLayout{
+			Name:   f.Name(),
+			Type:   types.TypeString(f.Type(), types.RelativeTo(tn.Pkg())),
+			Offset: offsets[i],
+			Size:   sizes.Sizeof(f.Type()),
+			Align:  sizes.Alignof(f.Type()),
+		}
+
+		end := result.Size
+		if i+1 < len(fields) {
+			end = offsets[i+1]
+		}
+		fl.Padding = end - fl.Offset - fl.Size
+
+		result.Padding += fl.Padding
+		result.Fields[i] = fl
+	}
+
+	optimal := append([]*types.Var(nil), fields...)
+	sort.SliceStable(optimal, func(i, j int) bool {
+		ai, aj := sizes.Alignof(optimal[i].Type()), sizes.Alignof(optimal[j].Type())
+		if ai != aj {
+			return ai > aj
+		}
+		return sizes.Sizeof(optimal[i].Type()) > sizes.Sizeof(optimal[j].Type())
+	})
+
+	result.OptimalSize = sizes.Sizeof(types.NewStruct(optimal, nil))
+	if result.OptimalSize >= result.Size {
+		optimal, result.OptimalSize = fields, result.Size
+	}
+
+	result.Optimal = make([]string, len(optimal))
+	for i, f := range optimal {
+		result.Optimal[i] = f.Name()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package goparser
+
+import (
+	"go/ast"
+)
+
+// limiter limits extraction of a composite literal, see WithMaxDepth and WithMaxElements;
+// a nil limiter doesn't limit anything
+type limiter struct {
+	maxDepth int
+	maxElems int
+
+	depth int
+	elems int
+
+	// truncated is set when some elements are omitted because of the limits
+	truncated bool
+}
+
+func (o *options) newLimiter() *limiter {
+	if o.maxDepth <= 0 && o.maxElems <= 0 {
+		return nil
+	}
+	return &limiter{maxDepth: o.maxDepth, maxElems: o.maxElems}
+}
+
+// enter reports whether a nested composite literal may be extracted, leave must be called after it if so
+func (l *limiter) enter() bool {
+	if l == nil {
+		return true
+	}
+
+	if l.maxDepth > 0 && l.depth >= l.maxDepth {
+		l.truncated = true
+		return false
+	}
+
+	l.depth++
+
+	return true
+}
+
+func (l *limiter) leave() {
+	if l != nil {
+		l.depth--
+	}
+}
+
+// add reports whether one more element may be extracted
+func (l *limiter) add() bool {
+	if l == nil || l.maxElems <= 0 {
+		return true
+	}
+
+	if l.elems >= l.maxElems {
+		l.truncated = true
+		return false
+	}
+
+	l.elems++
+
+	return true
+}
+
+// limit returns as many first elements as may be extracted
+func (l *limiter) limit(elts []ast.Expr) []ast.Expr {
+	if l == nil || l.maxElems <= 0 {
+		return elts
+	}
+
+	n := l.maxElems - l.elems
+	if n < len(elts) {
+		l.truncated = true
+		elts = elts[:n]
+	}
+
+	l.elems += len(elts)
+
+	return elts
+}
+
+// isTruncated reports whether some elements were omitted
+func (l *limiter) isTruncated() bool {
+	return l != nil && l.truncated
+}
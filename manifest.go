@@ -0,0 +1,156 @@
+package goparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"io"
+	"strings"
+)
+
+// TypeDoc contains an exported type declaration
+type TypeDoc struct {
+	Name string `json:"name"`
+
+	// Kind is struct, interface, func, map, slice, array, chan, pointer or ident (a defined type of another type)
+	Kind string `json:"kind"`
+	Doc  string `json:"doc,omitempty"`
+	Pos  string `json:"pos"`
+}
+
+// FuncDoc contains an exported function or method declaration
+type FuncDoc struct {
+	// Name is "Type.Method" for methods
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	Doc       string `json:"doc,omitempty"`
+	Pos       string `json:"pos"`
+}
+
+// Manifest is a machine-readable description of the API and configuration of a package:
+// exported types, functions and labeled values
+type Manifest struct {
+	Package string    `json:"package"`
+	Types   []TypeDoc `json:"types"`
+	Funcs   []FuncDoc `json:"funcs"`
+	Values  []Result  `json:"values"`
+}
+
+// NewManifest returns the manifest of parsed non-test files; values are collected by labels (see CollectValues)
+func NewManifest(g *GoParser, labels ...string) (*Manifest, error) {
+	m := &Manifest{
+		Types:  make([]TypeDoc, 0),
+		Funcs:  make([]FuncDoc, 0),
+		Values: make([]Result, 0),
+	}
+
+	for _, f := range g.files {
+		if strings.HasSuffix(f.path, "_test.go") {
+			continue
+		}
+
+		if m.Package == "" {
+			m.Package = f.ast.Name.Name
+		}
+
+		for _, d := range f.ast.Decls {
+			switch decl := d.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range decl.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if !ts.Name.IsExported() {
+						continue
+					}
+
+					doc := ts.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+
+					m.Types = append(m.Types, TypeDoc{
+						Name: ts.Name.Name,
+						Kind: typeKind(ts.Type),
+						Doc:  doc.Text(),
+						Pos:  f.fset.Position(ts.Pos()).String(),
+					})
+				}
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+				if rec := recvTypeName(decl); rec != "" && !ast.IsExported(rec) {
+					continue
+				}
+
+				sig, err := signature(f.fset, decl)
+				if err != nil {
+					return nil, err
+				}
+
+				m.Funcs = append(m.Funcs, FuncDoc{
+					Name:      funcName(decl),
+					Signature: sig,
+					Doc:       decl.Doc.Text(),
+					Pos:       f.fset.Position(decl.Pos()).String(),
+				})
+			}
+		}
+	}
+
+	if len(labels) > 0 {
+		m.Values = CollectValues(g, labels...).Results
+	}
+
+	return m, nil
+}
+
+// WriteJSON writes the manifest as indented JSON
+func (m *Manifest) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// signature returns the formatted function declaration without the doc and the body
+func signature(fset *token.FileSet, decl *ast.FuncDecl) (string, error) {
+	sig := *decl
+	sig.Doc, sig.Body = nil, nil
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, &sig); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// typeKind returns the kind of the type expression
+func typeKind(t ast.Expr) string {
+	switch tt := t.(type) {
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	case *ast.FuncType:
+		return "func"
+	case *ast.MapType:
+		return "map"
+	case *ast.ArrayType:
+		if tt.Len == nil {
+			return "slice"
+		}
+		return "array"
+	case *ast.ChanType:
+		return "chan"
+	case *ast.StarExpr:
+		return "pointer"
+	}
+
+	return "ident"
+}
@@ -0,0 +1,69 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// Merge returns a new instance of GoParser containing the files of all the parsers, e.g. to scan a set of files at once;
+// results keep the path of the file they came from in DeclInfo.Pos (and Result.File of exported results):
+//
+//	a, err := New("config/defaults.go")
+//	...
+//	b, err := NewFromSource("overrides.go", src)
+//	...
+//	for _, v := range GetBasicValues[string](Merge(a, b), "config") {
+//		fmt.Println(v.Pos.Filename, v.Name, v.Value)
+//	}
+//
+// Options of the first parser are used; type information is kept for the files of typed parsers.
+// Files that appear in several parsers are added once; pending edits of the parsers aren't carried over
+func Merge(parsers ...*GoParser) *GoParser {
+	g := &GoParser{opts: newOptions(nil)}
+	if len(parsers) > 0 {
+		g.opts = parsers[0].opts
+	}
+
+	seen := make(map[*file]bool)
+
+	for _, p := range parsers {
+		for _, f := range p.files {
+			if !seen[f] {
+				seen[f] = true
+				g.files = append(g.files, f)
+			}
+		}
+
+		if p.types != nil {
+			g.mergeTypes(p.types)
+		}
+	}
+
+	return g
+}
+
+// mergeTypes adds type information of another parser, the maps are keyed by nodes of distinct files
+func (g *GoParser) mergeTypes(ti *typeInfo) {
+	if g.types == nil {
+		g.types = &typeInfo{
+			info: &types.Info{
+				Types: make(map[ast.Expr]types.TypeAndValue),
+				Defs:  make(map[*ast.Ident]types.Object),
+				Uses:  make(map[*ast.Ident]types.Object),
+			},
+		}
+	}
+
+	for k, v := range ti.info.Types {
+		g.types.info.Types[k] = v
+	}
+	for k, v := range ti.info.Defs {
+		g.types.info.Defs[k] = v
+	}
+	for k, v := range ti.info.Uses {
+		g.types.info.Uses[k] = v
+	}
+
+	g.types.pkgs = append(g.types.pkgs, ti.pkgs...)
+	g.types.errs = append(g.types.errs, ti.errs...)
+}
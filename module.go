@@ -0,0 +1,78 @@
+package goparser
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// WithModule enables module-aware mode: the enclosing go.mod of each parsed file is located
+// and DeclInfo.Package is set to the import path of the file's package, e.g. "github.com/org/repo/internal/config";
+// in-memory sources are skipped
+func WithModule() Option {
+	return func(o *options) {
+		o.module = true
+	}
+}
+
+// module contains the path and the root directory of a module
+type module struct {
+	path string
+	dir  string
+}
+
+// setPackagePaths sets import paths of packages of the files read from disk
+func (g *GoParser) setPackagePaths() {
+	if !g.opts.module {
+		return
+	}
+
+	modules := make(map[string]*module)
+
+	for _, f := range g.files {
+		if f.inMemory || f.pkgPath != "" {
+			continue
+		}
+
+		dir, err := filepath.Abs(filepath.Dir(f.path))
+		if err != nil {
+			continue
+		}
+
+		mod := findModule(dir, modules)
+		if mod == nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(mod.dir, dir)
+		if err != nil {
+			continue
+		}
+
+		f.pkgPath = path.Join(mod.path, filepath.ToSlash(rel))
+	}
+}
+
+// findModule returns the module of the nearest go.mod in the directory or its parents, nil if there's none;
+// lookups are cached by directory
+func findModule(dir string, cache map[string]*module) *module {
+	if mod, ok := cache[dir]; ok {
+		return mod
+	}
+
+	var mod *module
+
+	if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		if modPath := modfile.ModulePath(data); modPath != "" {
+			mod = &module{path: modPath, dir: dir}
+		}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		mod = findModule(parent, cache)
+	}
+
+	cache[dir] = mod
+
+	return mod
+}
@@ -0,0 +1,46 @@
+package goparser
+
+import (
+	"go/ast"
+	"reflect"
+)
+
+// NestedMapLitValue contains map with literal keys and maps with literal keys and values as values
+type NestedMapLitValue[K, NK, V iLit] struct {
+	DeclInfo
+	Value map[K]map[NK]V
+}
+
+// GetNestedMapValues returns a list of values containing maps of maps with literal types as keys and values
+// by godoc label, e.g. settings grouped by section; the types of inner literals may be elided
+//
+//	// someLabel
+//	var testVar = map[string]map[string]int{"db": {"port": 5432}, "http": {"port": 8080}}
+func GetNestedMapValues[K, NK, V iLit](g *GoParser, docLabels ...string) []NestedMapLitValue[K, NK, V] {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	t := reflect.TypeOf(map[K]map[NK]V(nil))
+
+	return walkDecls(g, docMap, func(info DeclInfo, val ast.Expr) *NestedMapLitValue[K, NK, V] {
+		lim := g.opts.newLimiter()
+
+		v, ok := parseValue(val, t, lim)
+		if !ok || !v.IsValid() || (v.Len() == 0 && !lim.isTruncated()) {
+			return nil
+		}
+
+		info.Truncated = lim.isTruncated()
+
+		return &NestedMapLitValue[K, NK, V]{
+			DeclInfo: info,
+			Value:    v.Interface().(map[K]map[NK]V),
+		}
+	})
+}
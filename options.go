@@ -0,0 +1,313 @@
+package goparser
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Formatter formats Go source written by the package
+type Formatter func(src []byte) ([]byte, error)
+
+// Option configures GoParser
+type Option func(*options)
+
+type options struct {
+	preprocess func(path string, src []byte) []byte
+	include    []string
+	exclude    []string
+
+	followSymlinks bool
+	nestedModules  bool
+
+	types bool
+
+	format Formatter
+	header string
+
+	markers []string
+
+	resultCap int
+
+	usages bool
+
+	funcBodies bool
+
+	declaredTypes bool
+
+	resolveDepth int
+
+	maxDepth int
+	maxElems int
+
+	overlay map[string][]byte
+
+	module bool
+
+	docPolicy DocPolicy
+
+	maxLabelLen int
+	maxDocLines int
+
+	labelRegexps []*regexp.Regexp
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		format:       format.Source,
+		docPolicy:    DefaultDocPolicy,
+		resolveDepth: DefaultResolveDepth,
+		maxLabelLen:  DefaultMaxLabelLength,
+		maxDocLines:  DefaultMaxDocLines,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithPreprocessor sets a function that is called with the file path and its source before parsing,
+// e.g. to strip template markers from .gotmpl/.tpl files
+func WithPreprocessor(fn func(path string, src []byte) []byte) Option {
+	return func(o *options) {
+		o.preprocess = fn
+	}
+}
+
+// WithInclude sets glob patterns of files to parse in directory mode;
+// patterns are matched against slash-separated paths relative to the directory, `**` matches any number of directories;
+// patterns without "/" are matched against file names in any directory
+//
+//	WithInclude("**/config*.go", "*_config.go")
+func WithInclude(patterns ...string) Option {
+	return func(o *options) {
+		o.include = append(o.include, patterns...)
+	}
+}
+
+// WithExclude sets glob patterns of files to skip in directory mode, see WithInclude for the pattern syntax;
+// directories matching patterns ending with "/**" aren't walked at all
+//
+//	WithExclude("**/mocks/**", "**/zz_generated*.go")
+func WithExclude(patterns ...string) Option {
+	return func(o *options) {
+		o.exclude = append(o.exclude, patterns...)
+	}
+}
+
+// WithFollowSymlinks makes directory mode follow symbolic links to directories;
+// by default, like the go tool, only symlinked files are parsed
+func WithFollowSymlinks() Option {
+	return func(o *options) {
+		o.followSymlinks = true
+	}
+}
+
+// WithNestedModules makes directory mode descend into nested modules;
+// by default subdirectories containing a go.mod file are skipped
+func WithNestedModules() Option {
+	return func(o *options) {
+		o.nestedModules = true
+	}
+}
+
+// WithTypes enables typed mode: parsed files are type-checked (imported packages are type-checked from source),
+// which is required by type-aware features like GetStructLayout; type errors are available via TypeErrors
+func WithTypes() Option {
+	return func(o *options) {
+		o.types = true
+	}
+}
+
+// WithFormatter sets the formatter used for written and generated files instead of go/format,
+// e.g. to match gofumpt formatting:
+//
+//	WithFormatter(func(src []byte) ([]byte, error) {
+//		return gofumpt.Source(src, gofumpt.Options{})
+//	})
+func WithFormatter(fn Formatter) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.format = fn
+		}
+	}
+}
+
+// WithHeader sets a header (e.g. a license) for generated files; lines not starting with "//" are commented out,
+// the standard generated code marker is added unless the header contains one
+func WithHeader(header string) Option {
+	return func(o *options) {
+		o.header = header
+	}
+}
+
+// WithLabelMarkers sets label markers stripped from doc comment lines before comparing them with requested labels,
+// e.g. with WithLabelMarkers("@", "#") all of `// parser`, `// @parser` and `//#parser` match the "parser" label
+func WithLabelMarkers(markers ...string) Option {
+	return func(o *options) {
+		o.markers = append(o.markers, markers...)
+	}
+}
+
+// WithResultCapacity sets the initial capacity of slices returned by Get* functions,
+// e.g. the expected number of labeled values, to avoid reallocations while collecting them
+func WithResultCapacity(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.resultCap = n
+		}
+	}
+}
+
+// WithUsages makes extracted values contain the position of the first reference to them in their package
+// (DeclInfo.Usage), references in Example functions are preferred, e.g. to link documentation of config knobs
+// to a usage example; parse the whole package (NewFromDir) to find references in other files
+func WithUsages() Option {
+	return func(o *options) {
+		o.usages = true
+	}
+}
+
+// WithFuncBodies makes labeled constants and variables declared in function and method bodies extracted too,
+// e.g. encoder tables declared next to the code that uses them; the enclosing function is set in DeclInfo.Func
+//
+//	func (e *Encoder) Encode() {
+//		// someLabel
+//		const (
+//			// someLabel
+//			maxRun = 127
+//		)
+//		...
+//	}
+func WithFuncBodies() Option {
+	return func(o *options) {
+		o.funcBodies = true
+	}
+}
+
+// WithDeclaredTypes makes values with inferred types (InferValues, CollectValues) typed by the declared type
+// of their spec if it's a predeclared basic type, e.g. int64(8080) for `var port int64 = 8080` instead of
+// the type inferred from the literal, and float64(3) for `var ratio float64 = 3`;
+// values not representable by the declared type keep the inferred one, other declared types are in DeclInfo.Type
+func WithDeclaredTypes() Option {
+	return func(o *options) {
+		o.declaredTypes = true
+	}
+}
+
+// DefaultResolveDepth is the default number of identifiers followed to resolve values, see WithResolveDepth
+const DefaultResolveDepth = 8
+
+// WithResolveDepth sets how many identifiers are followed to resolve values initialized with identifiers
+// of other package-level variables or constants of the same file, DefaultResolveDepth by default; 0 disables resolution:
+//
+//	// someLabel
+//	var a = b // the value is 3, with WithResolveDepth(1) a is skipped
+//	var b = c
+//	const c = 3
+func WithResolveDepth(depth int) Option {
+	return func(o *options) {
+		o.resolveDepth = depth
+	}
+}
+
+// WithMaxDepth limits nesting of extracted composite literals, e.g. of slices of maps or nested struct literals;
+// deeper literals are omitted and values containing them are marked as truncated (DeclInfo.Truncated)
+func WithMaxDepth(depth int) Option {
+	return func(o *options) {
+		o.maxDepth = depth
+	}
+}
+
+// WithMaxElements limits the number of extracted elements of a composite value (including nested ones);
+// the rest are omitted and the value is marked as truncated (DeclInfo.Truncated)
+func WithMaxElements(n int) Option {
+	return func(o *options) {
+		o.maxElems = n
+	}
+}
+
+// Default limits of doc comments scanned for labels, see WithMaxLabelLength and WithMaxDocLines
+const (
+	DefaultMaxLabelLength = 1 << 10
+	DefaultMaxDocLines    = 1 << 10
+)
+
+// WithMaxLabelLength sets the maximum length of a doc comment line compared with labels, DefaultMaxLabelLength
+// by default; longer lines (e.g. megabyte-scale comments of machine-generated files) aren't matched
+// and the declaration is marked (DeclInfo.DocTruncated); 0 disables the limit
+func WithMaxLabelLength(n int) Option {
+	return func(o *options) {
+		o.maxLabelLen = n
+	}
+}
+
+// WithMaxDocLines sets the maximum number of doc comment lines of a declaration scanned for labels,
+// DefaultMaxDocLines by default; the rest aren't matched and the declaration is marked (DeclInfo.DocTruncated);
+// 0 disables the limit
+func WithMaxDocLines(n int) Option {
+	return func(o *options) {
+		o.maxDocLines = n
+	}
+}
+
+// WithLabelRegexps sets regexps matching labels in addition to the labels passed to extraction functions,
+// which may be called without labels then, e.g. to select `cfg`, `cfg:db` and `cfg:http` without enumerating them:
+//
+//	p, err := New(path, WithLabelRegexps(regexp.MustCompile(`^cfg(:[a-z]+)?$`)))
+//	values := GetBasicValues[int](p)
+//
+// doc comment lines are matched as a whole and without trailing label arguments, so regexps should be anchored
+// not to match prose lines
+func WithLabelRegexps(res ...*regexp.Regexp) Option {
+	return func(o *options) {
+		o.labelRegexps = append(o.labelRegexps, res...)
+	}
+}
+
+// WithOverlay sets source of files overriding their content on disk, e.g. unsaved editor buffers,
+// like the overlay of go/packages; keys are file paths, files missing on disk are added in directory mode
+func WithOverlay(overlay map[string][]byte) Option {
+	return func(o *options) {
+		if o.overlay == nil {
+			o.overlay = make(map[string][]byte, len(overlay))
+		}
+		for p, src := range overlay {
+			o.overlay[overlayKey(p)] = src
+		}
+	}
+}
+
+// readFile returns the source of the file from the overlay or from disk
+func (o *options) readFile(path string) ([]byte, error) {
+	if src, ok := o.overlay[overlayKey(path)]; ok {
+		return src, nil
+	}
+	return os.ReadFile(path)
+}
+
+// overlayKey returns the absolute path of the file
+func overlayKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// labelText returns the text of a doc comment line with comment and label markers stripped
+func (o *options) labelText(comment string) string {
+	txt := strings.TrimLeft(comment, "/ ")
+	if strings.HasPrefix(comment, "/*") {
+		txt = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(comment, "/*"), "*/"))
+	}
+
+	for _, m := range o.markers {
+		if m != "" && strings.HasPrefix(txt, m) {
+			return strings.TrimLeft(txt[len(m):], " ")
+		}
+	}
+
+	return txt
+}
@@ -0,0 +1,82 @@
+package goparser
+
+import (
+	"go/ast"
+)
+
+// KV is a map entry with literal key and value
+type KV[K, V iLit] struct {
+	Key   K
+	Value V
+}
+
+// OrderedMapLitValue contains entries of a map literal in the order they're written in the source
+type OrderedMapLitValue[K, V iLit] struct {
+	DeclInfo
+	Value []KV[K, V]
+}
+
+// Map returns entries as a map
+func (v OrderedMapLitValue[K, V]) Map() map[K]V {
+	m := make(map[K]V, len(v.Value))
+	for _, kv := range v.Value {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+// GetOrderedMapValues is like GetMapValues, but returns entries of maps in declaration order,
+// e.g. to generate files matching the source
+//
+//	// someLabel
+//	var testVar = map[string]int{"b": 2, "a": 1} // []KV[string, int]{{"b", 2}, {"a", 1}}
+func GetOrderedMapValues[K, V iLit](g *GoParser, docLabels ...string) []OrderedMapLitValue[K, V] {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return walkDecls(g, docMap, func(info DeclInfo, val ast.Expr) *OrderedMapLitValue[K, V] {
+		cmpVal, ok := val.(*ast.CompositeLit)
+		if !ok {
+			return nil
+		}
+
+		if _, ok := cmpVal.Type.(*ast.MapType); !ok {
+			return nil
+		}
+
+		lim := g.opts.newLimiter()
+		elts := lim.limit(cmpVal.Elts)
+		info.Truncated = lim.isTruncated()
+
+		entries := make([]KV[K, V], 0, len(elts))
+		for _, elt := range elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			k, keyOk := parseElt[K](kv.Key)
+			v, valOk := parseElt[V](kv.Value)
+			if !keyOk || !valOk || k == nil || v == nil {
+				continue
+			}
+
+			entries = append(entries, KV[K, V]{Key: *k, Value: *v})
+		}
+
+		if len(entries) == 0 {
+			return nil
+		}
+
+		return &OrderedMapLitValue[K, V]{
+			DeclInfo: info,
+			Value:    entries,
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package goparser
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OutputFS is an in-memory staging area for generated files; it implements fs.FS,
+// so generated output can be inspected and tested before it's flushed to disk
+type OutputFS struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewOutputFS returns a new empty OutputFS
+func NewOutputFS() *OutputFS {
+	return &OutputFS{files: make(map[string][]byte)}
+}
+
+// WriteFile writes the file by a slash-separated relative path (see fs.ValidPath)
+func (o *OutputFS) WriteFile(name string, data []byte) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.isDir(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: errors.New("is a directory")}
+	}
+
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if _, ok := o.files[dir]; ok {
+			return &fs.PathError{Op: "write", Path: name, Err: errors.New("not a directory")}
+		}
+	}
+
+	o.files[name] = append([]byte(nil), data...)
+
+	return nil
+}
+
+// WriteGenFile writes the formatted source of the generated file
+func (o *OutputFS) WriteGenFile(name string, f *GenFile) error {
+	src, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+	return o.WriteFile(name, src)
+}
+
+// Names returns sorted paths of all files
+func (o *OutputFS) Names() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return sortedKeys(o.files)
+}
+
+// ReadFile implements fs.ReadFileFS
+func (o *OutputFS) ReadFile(name string) ([]byte, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	data, ok := o.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return append([]byte(nil), data...), nil
+}
+
+// Open implements fs.FS
+func (o *OutputFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if data, ok := o.files[name]; ok {
+		return &memFile{
+			info:   memFileInfo{name: path.Base(name), size: int64(len(data))},
+			Reader: bytes.NewReader(data),
+		}, nil
+	}
+
+	if !o.isDir(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &memDir{
+		info:    memFileInfo{name: path.Base(name), dir: true},
+		entries: o.dirEntries(name),
+	}, nil
+}
+
+// Flush writes all files into the directory, creating subdirectories as needed
+func (o *OutputFS) Flush(dir string) error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	for _, name := range sortedKeys(o.files) {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(p, o.files[name], 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *OutputFS) isDir(name string) bool {
+	if name == "." {
+		return true
+	}
+
+	prefix := name + "/"
+	for n := range o.files {
+		if strings.HasPrefix(n, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *OutputFS) dirEntries(dir string) []fs.DirEntry {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+
+	seen := make(map[string]fs.DirEntry)
+	for n, data := range o.files {
+		if !strings.HasPrefix(n, prefix) {
+			continue
+		}
+
+		rest := n[len(prefix):]
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			seen[rest[:i]] = fs.FileInfoToDirEntry(memFileInfo{name: rest[:i], dir: true})
+		} else {
+			seen[rest] = fs.FileInfoToDirEntry(memFileInfo{name: rest, size: int64(len(data))})
+		}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(seen))
+	for _, name := range sortedKeys(seen) {
+		entries = append(entries, seen[name])
+	}
+
+	return entries
+}
+
+type memFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.dir }
+func (i memFileInfo) Sys() any           { return nil }
+
+func (i memFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o755
+	}
+	return 0o644
+}
+
+type memFile struct {
+	*bytes.Reader
+	info memFileInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+type memDir struct {
+	info    memFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir implements fs.ReadDirFile
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+
+	return rest[:n], nil
+}
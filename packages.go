@@ -0,0 +1,86 @@
+package goparser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// NewFromPackages returns a new instance of GoParser for packages matching the pattern (e.g. "./internal/config")
+// loaded with golang.org/x/tools/go/packages from the dir with full type information, like in typed mode (see WithTypes);
+// labeled values initialized with constant expressions, e.g. constants of other packages, are resolved to their values
+//
+//	p, err := NewFromPackages(".", "./internal/config")
+func NewFromPackages(dir, pattern string, opts ...Option) (*GoParser, error) {
+	o := newOptions(opts)
+
+	var mu sync.Mutex
+	sources := make(map[string][]byte)
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports |
+			packages.NeedDeps | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:  dir,
+		Fset: token.NewFileSet(),
+		ParseFile: func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+			mu.Lock()
+			sources[filename] = src
+			mu.Unlock()
+
+			return parser.ParseFile(fset, filename, src, parser.ParseComments)
+		},
+	}
+
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	ti := &typeInfo{
+		info: &types.Info{
+			Types: make(map[ast.Expr]types.TypeAndValue),
+			Defs:  make(map[*ast.Ident]types.Object),
+			Uses:  make(map[*ast.Ident]types.Object),
+		},
+	}
+
+	g := &GoParser{opts: o, types: ti}
+
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			ti.errs = append(ti.errs, e)
+		}
+
+		if pkg.Types != nil {
+			ti.pkgs = append(ti.pkgs, pkg.Types)
+		}
+
+		if pkg.TypesInfo != nil {
+			for k, v := range pkg.TypesInfo.Types {
+				ti.info.Types[k] = v
+			}
+			for k, v := range pkg.TypesInfo.Defs {
+				ti.info.Defs[k] = v
+			}
+			for k, v := range pkg.TypesInfo.Uses {
+				ti.info.Uses[k] = v
+			}
+		}
+
+		for _, f := range pkg.Syntax {
+			path := cfg.Fset.File(f.Pos()).Name()
+			g.files = append(g.files, &file{fset: cfg.Fset, path: path, ast: f, src: sources[path], pkgPath: pkg.PkgPath})
+		}
+	}
+
+	if len(g.files) == 0 {
+		return nil, fmt.Errorf("no Go files in packages matching %q", pattern)
+	}
+
+	return g, nil
+}
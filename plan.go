@@ -0,0 +1,196 @@
+package goparser
+
+import "go/ast"
+
+// Plan contains queries declared up front and executed together: value queries share a single walk
+// over labeled declarations and function queries share a single walk over function declarations
+//
+//	plan := NewPlan()
+//	PlanBasicValues[string](plan, "names", "someLabel")
+//	PlanSliceValues[int64](plan, "ports", "net")
+//	PlanFuncNamesByResults(plan, "fallible", "error")
+//
+//	res := plan.Execute(p)
+//	names := PlanResult[LitValue[string]](res, "names")
+//	fallible := PlanResult[string](res, "fallible")
+type Plan struct {
+	values []valueQuery
+	funcs  []funcQuery
+}
+
+// valueQuery is a query of labeled values
+type valueQuery struct {
+	key    string
+	labels map[string]struct{}
+
+	// start returns functions adding a value to the results of the query and returning them
+	start func(g *GoParser) (add func(f *file, info DeclInfo, val ast.Expr), results func() any)
+}
+
+// funcQuery is a query of function names
+type funcQuery struct {
+	key   string
+	match func(g *GoParser, decl *ast.FuncDecl) bool
+}
+
+// PlanResults contains results of plan queries by key, see PlanResult
+type PlanResults map[string]any
+
+// NewPlan returns an empty plan; keys of its queries must be unique, a query replaces results of a previous one
+// with the same key
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// PlanBasicValues adds a query of basic values to the plan, see GetBasicValues; results are []LitValue[V]
+func PlanBasicValues[V iLit](p *Plan, key string, docLabels ...string) {
+	addValueQuery(p, key, docLabels, anyFile(basicValue[V]))
+}
+
+// PlanSliceValues adds a query of slice values to the plan, see GetSliceValues; results are []SliceLitValue[V]
+func PlanSliceValues[V iLit](p *Plan, key string, docLabels ...string) {
+	addValueQuery(p, key, docLabels, anyFile(sliceValue[V]))
+}
+
+// PlanMapValues adds a query of map values to the plan, see GetMapValues; results are []MapLitValue[K, V]
+func PlanMapValues[K, V iLit](p *Plan, key string, docLabels ...string) {
+	addValueQuery(p, key, docLabels, anyFile(mapValue[K, V]))
+}
+
+// PlanInferValues adds a query of values with inferred types to the plan, see InferValues; results are []AnyValue
+func PlanInferValues(p *Plan, key string, docLabels ...string) {
+	addValueQuery(p, key, docLabels, anyValue)
+}
+
+// PlanFuncNames adds a query of function names to the plan, see GetFuncNames; results are []string
+func PlanFuncNames(p *Plan, key string, recType string, paramTypes ...string) {
+	p.funcs = append(p.funcs, funcQuery{key: key, match: func(_ *GoParser, decl *ast.FuncDecl) bool {
+		return funcMatches(decl, recType, paramTypes)
+	}})
+}
+
+// PlanFuncNamesByResults adds a query of function names to the plan, see GetFuncNamesByResults; results are []string
+func PlanFuncNamesByResults(p *Plan, key string, resultTypes ...string) {
+	p.funcs = append(p.funcs, funcQuery{key: key, match: func(g *GoParser, decl *ast.FuncDecl) bool {
+		return g.resultsMatch(decl, resultTypes)
+	}})
+}
+
+func addValueQuery[T any](p *Plan, key string, docLabels []string, newFn func(g *GoParser) func(f *file, info DeclInfo, val ast.Expr) *T) {
+	labels := make(map[string]struct{}, len(docLabels))
+	for _, l := range docLabels {
+		labels[l] = struct{}{}
+	}
+
+	p.values = append(p.values, valueQuery{
+		key:    key,
+		labels: labels,
+		start: func(g *GoParser) (func(f *file, info DeclInfo, val ast.Expr), func() any) {
+			values := newResults[T](g)
+			fn := newFn(g)
+
+			add := func(f *file, info DeclInfo, val ast.Expr) {
+				if res := fn(f, info, val); res != nil {
+					values = append(values, *res)
+				}
+			}
+
+			return add, func() any { return values }
+		},
+	})
+}
+
+// anyFile adapts a function converting labeled values of any file to addValueQuery
+func anyFile[T any](newFn func(g *GoParser) func(info DeclInfo, val ast.Expr) *T) func(g *GoParser) func(f *file, info DeclInfo, val ast.Expr) *T {
+	return func(g *GoParser) func(f *file, info DeclInfo, val ast.Expr) *T {
+		fn := newFn(g)
+		return func(_ *file, info DeclInfo, val ast.Expr) *T {
+			return fn(info, val)
+		}
+	}
+}
+
+// Execute runs the queries of the plan and returns their results by key
+func (p *Plan) Execute(g *GoParser) PlanResults {
+	res := make(PlanResults, len(p.values)+len(p.funcs))
+
+	if len(p.values) > 0 {
+		docMap := make(map[string]struct{})
+		adds := make([]func(f *file, info DeclInfo, val ast.Expr), len(p.values))
+		results := make([]func() any, len(p.values))
+		matchers := make([]*labelMatcher, len(p.values))
+
+		for i, q := range p.values {
+			for l := range q.labels {
+				docMap[l] = struct{}{}
+			}
+			adds[i], results[i] = q.start(g)
+			matchers[i] = newLabelMatcher(q.labels, g.opts.labelRegexps)
+		}
+
+		appendDeclsFunc[struct{}](nil, g, docMap, func(dst []struct{}, f *file, info DeclInfo, val ast.Expr) []struct{} {
+			for i, q := range p.values {
+				if qInfo, ok := q.info(info, matchers[i]); ok {
+					adds[i](f, qInfo, val)
+				}
+			}
+			return dst
+		})
+
+		for i, q := range p.values {
+			res[q.key] = results[i]()
+		}
+	}
+
+	if len(p.funcs) > 0 {
+		names := make([][]string, len(p.funcs))
+		for i := range names {
+			names[i] = make([]string, 0)
+		}
+
+		for _, f := range g.files {
+			for _, d := range f.ast.Decls {
+				decl, ok := d.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+
+				for i, q := range p.funcs {
+					if q.match(g, decl) {
+						names[i] = append(names[i], decl.Name.Name)
+					}
+				}
+			}
+		}
+
+		for i, q := range p.funcs {
+			res[q.key] = names[i]
+		}
+	}
+
+	return res
+}
+
+// info returns the declaration info with labels matched by the query only, false if none of them matched
+func (q valueQuery) info(info DeclInfo, match *labelMatcher) (DeclInfo, bool) {
+	labels := make([]string, 0, len(info.Labels))
+	for _, l := range info.Labels {
+		if match.match(l) {
+			labels = append(labels, l)
+		}
+	}
+
+	if len(labels) == 0 {
+		return info, false
+	}
+
+	info.Doc, info.Label, info.Labels = labels[0], labels[0], labels
+
+	return info, true
+}
+
+// PlanResult returns results of the query with the key, nil if there's no such query or results are of another type
+func PlanResult[T any](r PlanResults, key string) []T {
+	values, _ := r[key].([]T)
+	return values
+}
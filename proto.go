@@ -0,0 +1,485 @@
+package goparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Protobuf encoding of reports as defined in proto/goparser.proto;
+// the wire format is written by hand to keep the package free of protobuf dependencies
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errProtoTruncated = errors.New("proto: truncated message")
+
+// MarshalProto returns the report encoded as the goparser.v1.Report protobuf message
+func (r *Report) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendStringField(b, 1, r.SchemaVersion)
+
+	for _, res := range r.Results {
+		m, err := res.MarshalProto()
+		if err != nil {
+			return nil, err
+		}
+		b = appendBytesField(b, 2, m)
+	}
+
+	return b, nil
+}
+
+// UnmarshalProtoReport decodes the goparser.v1.Report protobuf message
+func UnmarshalProtoReport(b []byte) (*Report, error) {
+	r := &Report{Results: make([]Result, 0)}
+
+	err := readFields(b, func(num int, wire int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			r.SchemaVersion = string(data)
+		case 2:
+			res, err := UnmarshalProtoResult(data)
+			if err != nil {
+				return err
+			}
+			r.Results = append(r.Results, res)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// MarshalProto returns the result encoded as the goparser.v1.Result protobuf message
+func (r Result) MarshalProto() ([]byte, error) {
+	var b []byte
+
+	b = appendStringField(b, 1, r.Kind)
+	b = appendStringField(b, 2, r.Name)
+	b = appendStringField(b, 3, r.Doc)
+	for _, l := range r.Labels {
+		b = appendBytesField(b, 4, []byte(l))
+	}
+	b = appendStringField(b, 5, r.Checksum)
+	for _, k := range sortedKeys(r.Args) {
+		var entry []byte
+		entry = appendStringField(entry, 1, k)
+		entry = appendStringField(entry, 2, r.Args[k])
+		b = appendBytesField(b, 6, entry)
+	}
+	b = appendStringField(b, 7, r.File)
+	b = appendVarintField(b, 8, uint64(r.Line))
+	b = appendStringField(b, 9, r.Usage)
+	if r.Truncated {
+		b = appendVarintField(b, 10, 1)
+	}
+
+	switch r.Kind {
+	case KindBasic, KindSelectorRef, KindAlloc:
+		v, err := marshalProtoValue(r.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		b = appendBytesField(b, 11, v)
+	case KindSlice:
+		rv := reflect.ValueOf(r.Value)
+		if rv.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("%s: unsupported slice value %T", r.Name, r.Value)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			v, err := marshalProtoValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", r.Name, err)
+			}
+			b = appendBytesField(b, 12, v)
+		}
+	case KindMap:
+		entries, _ := r.Value.([]Entry)
+		for _, e := range entries {
+			m, err := marshalProtoEntry(e)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", r.Name, err)
+			}
+			b = appendBytesField(b, 13, m)
+		}
+	case KindSliceMap:
+		maps, _ := r.Value.([][]Entry)
+		for _, entries := range maps {
+			var m []byte
+			for _, e := range entries {
+				em, err := marshalProtoEntry(e)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", r.Name, err)
+				}
+				m = appendBytesField(m, 1, em)
+			}
+			b = appendBytesField(b, 14, m)
+		}
+	}
+
+	if r.Format != nil {
+		b = appendBytesField(b, 15, marshalProtoFormat(*r.Format))
+	}
+	b = appendStringField(b, 16, r.Package)
+	b = appendVarintField(b, 17, uint64(r.LabelLine))
+	b = appendStringField(b, 18, r.Func)
+	b = appendStringField(b, 19, r.Type)
+	if r.DocTruncated {
+		b = appendVarintField(b, 20, 1)
+	}
+	b = appendStringField(b, 21, r.Label)
+	b = appendStringField(b, 22, r.Description)
+
+	return b, nil
+}
+
+// UnmarshalProtoResult decodes the goparser.v1.Result protobuf message;
+// integers are decoded as int64 or uint64 and floats as float64
+func UnmarshalProtoResult(b []byte) (Result, error) {
+	var r Result
+	var values []any
+	var entries []Entry
+	var maps [][]Entry
+
+	err := readFields(b, func(num int, wire int, v uint64, data []byte) error {
+		var err error
+
+		switch num {
+		case 1:
+			r.Kind = string(data)
+		case 2:
+			r.Name = string(data)
+		case 3:
+			r.Doc = string(data)
+		case 4:
+			r.Labels = append(r.Labels, string(data))
+		case 5:
+			r.Checksum = string(data)
+		case 6:
+			var k, val string
+			err = readFields(data, func(num int, _ int, _ uint64, data []byte) error {
+				if num == 1 {
+					k = string(data)
+				} else if num == 2 {
+					val = string(data)
+				}
+				return nil
+			})
+			if r.Args == nil {
+				r.Args = make(map[string]string)
+			}
+			r.Args[k] = val
+		case 7:
+			r.File = string(data)
+		case 8:
+			r.Line = int(int32(v))
+		case 9:
+			r.Usage = string(data)
+		case 10:
+			r.Truncated = v != 0
+		case 11:
+			r.Value, err = unmarshalProtoValue(data)
+		case 12:
+			var val any
+			val, err = unmarshalProtoValue(data)
+			values = append(values, val)
+		case 13:
+			var e Entry
+			e, err = unmarshalProtoEntry(data)
+			entries = append(entries, e)
+		case 14:
+			m := make([]Entry, 0)
+			err = readFields(data, func(num int, _ int, _ uint64, data []byte) error {
+				if num != 1 {
+					return nil
+				}
+				e, err := unmarshalProtoEntry(data)
+				m = append(m, e)
+				return err
+			})
+			maps = append(maps, m)
+		case 15:
+			r.Format, err = unmarshalProtoFormat(data)
+		case 16:
+			r.Package = string(data)
+		case 17:
+			r.LabelLine = int(int32(v))
+		case 18:
+			r.Func = string(data)
+		case 19:
+			r.Type = string(data)
+		case 20:
+			r.DocTruncated = v != 0
+		case 21:
+			r.Label = string(data)
+		case 22:
+			r.Description = string(data)
+		}
+
+		return err
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	switch r.Kind {
+	case KindSlice:
+		r.Value = values
+	case KindMap:
+		r.Value = entries
+	case KindSliceMap:
+		r.Value = maps
+	}
+
+	return r, nil
+}
+
+func marshalProtoEntry(e Entry) ([]byte, error) {
+	k, err := marshalProtoValue(e.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := marshalProtoValue(e.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	b = appendBytesField(b, 1, k)
+	b = appendBytesField(b, 2, v)
+	if e.Format != nil {
+		b = appendBytesField(b, 3, marshalProtoFormat(*e.Format))
+	}
+
+	return b, nil
+}
+
+func unmarshalProtoEntry(b []byte) (Entry, error) {
+	var e Entry
+
+	err := readFields(b, func(num int, _ int, _ uint64, data []byte) error {
+		var err error
+
+		switch num {
+		case 1:
+			e.Key, err = unmarshalProtoValue(data)
+		case 2:
+			e.Value, err = unmarshalProtoValue(data)
+		case 3:
+			e.Format, err = unmarshalProtoFormat(data)
+		}
+
+		return err
+	})
+
+	return e, err
+}
+
+func marshalProtoFormat(f NumFormat) []byte {
+	var b []byte
+	b = appendVarintField(b, 1, uint64(f.Base))
+	b = appendStringField(b, 2, f.Prefix)
+	if f.Upper {
+		b = appendVarintField(b, 3, 1)
+	}
+	b = appendVarintField(b, 4, uint64(f.Group))
+
+	return b
+}
+
+func unmarshalProtoFormat(b []byte) (*NumFormat, error) {
+	f := &NumFormat{}
+
+	err := readFields(b, func(num int, _ int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			f.Base = int(int32(v))
+		case 2:
+			f.Prefix = string(data)
+		case 3:
+			f.Upper = v != 0
+		case 4:
+			f.Group = int(int32(v))
+		}
+		return nil
+	})
+
+	return f, err
+}
+
+// marshalProtoValue encodes a basic value as the goparser.v1.Value message
+func marshalProtoValue(value any) ([]byte, error) {
+	var b []byte
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		// the field is always written to keep empty strings distinguishable from unset values
+		b = appendTag(b, 1, wireBytes)
+		b = appendVarint(b, uint64(len(v.String())))
+		b = append(b, v.String()...)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		b = appendTag(b, 2, wireVarint)
+		b = appendVarint(b, uint64(i<<1)^uint64(i>>63))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		b = appendTag(b, 3, wireVarint)
+		b = appendVarint(b, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		b = appendTag(b, 4, wireFixed64)
+		b = appendFixed64(b, math.Float64bits(v.Float()))
+	case reflect.Bool:
+		b = appendTag(b, 5, wireVarint)
+		if v.Bool() {
+			b = appendVarint(b, 1)
+		} else {
+			b = appendVarint(b, 0)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+
+	return b, nil
+}
+
+// unmarshalProtoValue decodes the goparser.v1.Value message
+func unmarshalProtoValue(b []byte) (any, error) {
+	var value any
+
+	err := readFields(b, func(num int, _ int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			value = string(data)
+		case 2:
+			value = int64(v>>1) ^ -int64(v&1)
+		case 3:
+			value = v
+		case 4:
+			value = math.Float64frombits(v)
+		case 5:
+			value = v != 0
+		}
+		return nil
+	})
+
+	return value, err
+}
+
+func appendTag(b []byte, num int, wire int) []byte {
+	return appendVarint(b, uint64(num)<<3|uint64(wire))
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendFixed64(b []byte, v uint64) []byte {
+	for i := 0; i < 8; i++ {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
+
+// appendVarintField appends a varint field unless it has the default zero value
+func appendVarintField(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendTag(b, num, wireVarint)
+	return appendVarint(b, v)
+}
+
+// appendStringField appends a string field unless it's empty
+func appendStringField(b []byte, num int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendBytesField(b, num, []byte(s))
+}
+
+func appendBytesField(b []byte, num int, data []byte) []byte {
+	b = appendTag(b, num, wireBytes)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func readVarint(b []byte) (uint64, int, error) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, i + 1, nil
+		}
+	}
+	return 0, 0, errProtoTruncated
+}
+
+// readFields calls fn for each field of the message with its number, wire type,
+// the value of varint and fixed fields and the data of length-delimited ones
+func readFields(b []byte, fn func(num int, wire int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, n, err := readVarint(b)
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+
+		num, wire := int(tag>>3), int(tag&7)
+
+		var v uint64
+		var data []byte
+
+		switch wire {
+		case wireVarint:
+			if v, n, err = readVarint(b); err != nil {
+				return err
+			}
+			b = b[n:]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if wire == wireFixed32 {
+				size = 4
+			}
+			if len(b) < size {
+				return errProtoTruncated
+			}
+			for i := 0; i < size; i++ {
+				v |= uint64(b[i]) << (8 * i)
+			}
+			b = b[size:]
+		case wireBytes:
+			l, n, err := readVarint(b)
+			if err != nil {
+				return err
+			}
+			b = b[n:]
+			if uint64(len(b)) < l {
+				return errProtoTruncated
+			}
+			data, b = b[:l], b[l:]
+		default:
+			return fmt.Errorf("proto: unsupported wire type %d of field %d", wire, num)
+		}
+
+		if err = fn(num, wire, v, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package goparser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// GenerateRegistry appends a runtime registry of the values to the generated file, so services can expose
+// and tweak defaults at runtime; it must follow GenerateDefaults with the same results, which declares the variables:
+//
+//	f := NewGenFile("config")
+//	_ = GenerateDefaults(f, report.Results)
+//	_ = GenerateRegistry(f, report.Results)
+//
+// The generated Registry maps names to RegistryVar accessors, which implement expvar.Var and can be published
+// with PublishRegistry; values of KindSelectorRef and KindAlloc results are read-only.
+// Accessors are synchronized with each other, but not with direct reads of the variables
+func GenerateRegistry(f *GenFile, results []Result) error {
+	f.addSources(results)
+
+	f.Printf(registryHeader)
+
+	f.Printf("var Registry = map[string]RegistryVar{\n")
+
+	for _, r := range results {
+		f.Printf("\t%s: {\n", strconv.Quote(r.Name))
+		f.Printf("\t\tget: func() any { return %s },\n", r.Name)
+
+		if r.Kind != KindSelectorRef && r.Kind != KindAlloc {
+			typ, err := goType(r.Value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", r.Name, err)
+			}
+
+			f.Printf("\t\tset: func(v any) error {\n")
+			f.Printf("\t\t\tx, ok := v.(%s)\n", typ)
+			f.Printf("\t\t\tif !ok {\n")
+			f.Printf("\t\t\t\treturn fmt.Errorf(\"%s: can't set %%T, expected %s\", v)\n", r.Name, typ)
+			f.Printf("\t\t\t}\n")
+			f.Printf("\t\t\t%s = x\n", r.Name)
+			f.Printf("\t\t\treturn nil\n")
+			f.Printf("\t\t},\n")
+		}
+
+		f.Printf("\t},\n")
+	}
+
+	f.Printf("}\n")
+
+	return nil
+}
+
+// registryHeader contains declarations of the generated registry
+const registryHeader = `
+// RegistryVar is a runtime accessor of a default value, it implements expvar.Var
+type RegistryVar struct {
+	get func() any
+	set func(v any) error
+}
+
+var registryMu sync.RWMutex
+
+// Get returns the current value
+func (v RegistryVar) Get() any {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return v.get()
+}
+
+// Set replaces the value, it must be of the type of the variable
+func (v RegistryVar) Set(value any) error {
+	if v.set == nil {
+		return errors.New("the value is read-only")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	return v.set(value)
+}
+
+// String returns the current value as JSON
+func (v RegistryVar) String() string {
+	b, err := json.Marshal(v.Get())
+	if err != nil {
+		return strconv.Quote(err.Error())
+	}
+	return string(b)
+}
+
+// PublishRegistry publishes the registry variables with expvar, names are prefixed with the prefix
+func PublishRegistry(prefix string) {
+	for name, v := range Registry {
+		expvar.Publish(prefix+name, v)
+	}
+}
+
+// Registry contains accessors of the defaults by name
+`
+
+// goType returns the Go type of a variable initialized with the literal of the value
+func goType(value any) (string, error) {
+	lit, typ, err := goLiteral(value)
+	if err != nil {
+		return "", err
+	}
+
+	if typ != "" {
+		return typ, nil
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String:
+		return "string", nil
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.Int:
+		return "int", nil
+	case reflect.Float64:
+		return "float64", nil
+	}
+
+	// composite literals start with their type
+	return lit[:strings.Index(lit, "{")], nil
+}
@@ -0,0 +1,239 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// resolve follows identifiers referring to package-level variables and constants of the file
+// up to the depth (see WithResolveDepth) and returns the expression they're initialized with
+//
+//	var a = b // resolves to 3
+//	var b = 3
+//
+// Conversions of values to basic types or types declared in the file are unwrapped (see unconvert)
+func (f *file) resolve(val ast.Expr, depth int) ast.Expr {
+	val = f.unconvert(val)
+
+	for i := 0; i < depth; i++ {
+		id, ok := val.(*ast.Ident)
+		if !ok {
+			return val
+		}
+
+		next := f.initExpr(id)
+		if next == nil {
+			return val
+		}
+
+		val = f.unconvert(next)
+	}
+
+	return val
+}
+
+// unconvert unwraps parentheses, addresses of composite literals and single-argument conversions
+// to types declared in the file and to basic types literals of the same kind are converted to:
+//
+//	var a = float32(3.14)           // 3.14
+//	var b = MyString("x")           // "x"
+//	var c = ([]int{1, 2})           // []int{1, 2}
+//	var d = &map[string]int{"a": 1} // map[string]int{"a": 1}
+//
+// other conversions to basic types are kept to be evaluated as constants (see convertConst):
+//
+//	var e = float32(3)       // float32(3), 3.0
+//	var f = string(rune(65)) // string(rune(65)), "A"
+func (f *file) unconvert(val ast.Expr) ast.Expr {
+	for {
+		val = ast.Unparen(val)
+
+		if lit, ok := compositeLit(val); ok {
+			return lit
+		}
+
+		call, ok := val.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 || call.Ellipsis.IsValid() || !f.isTypeName(call.Fun) {
+			return val
+		}
+
+		if name := f.basicTypeName(call.Fun); name != "" && !keepsKind(name, call.Args[0]) {
+			return val
+		}
+
+		val = call.Args[0]
+	}
+}
+
+// convType returns the type of the conversion the value is written as, see unconvert; nil for other values
+func (f *file) convType(val ast.Expr) ast.Expr {
+	call, ok := ast.Unparen(val).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || call.Ellipsis.IsValid() || !f.isTypeName(call.Fun) {
+		return nil
+	}
+
+	return ast.Unparen(call.Fun)
+}
+
+// keepsKind reports whether the value is a literal of the kind values of the basic type are written with,
+// so that the conversion doesn't change it, e.g. 3 converted to int, but not 3 converted to float64
+func keepsKind(typeName string, val ast.Expr) bool {
+	if id, ok := ast.Unparen(val).(*ast.Ident); ok {
+		_, isBool := boolIdent(id)
+		return isBool && typeName == "bool"
+	}
+
+	lit, ok := basicLit(val)
+	if !ok {
+		return false
+	}
+
+	switch typeName {
+	case "string":
+		return lit.Kind == token.STRING
+	case "float32", "float64":
+		return lit.Kind == token.FLOAT
+	case "complex64", "complex128":
+		return lit.Kind == token.IMAG
+	case "bool":
+		return false
+	}
+
+	// integer types
+	return lit.Kind == token.INT || lit.Kind == token.CHAR
+}
+
+// maxTypeChain limits following declarations of types declared with other types of the file
+const maxTypeChain = 16
+
+// basicTypeName returns the name of the predeclared basic type the type is or is declared with in the file,
+// e.g. "int" of `type Port int`; empty for other types and types of other files and packages
+func (f *file) basicTypeName(typ ast.Expr) string {
+	for i := 0; i < maxTypeChain; i++ {
+		id, ok := ast.Unparen(typ).(*ast.Ident)
+		if !ok {
+			return ""
+		}
+
+		obj := id.Obj
+		if obj == nil && f.ast.Scope != nil {
+			obj = f.ast.Scope.Lookup(id.Name)
+		}
+
+		if obj == nil {
+			if _, ok := basicTypes[id.Name]; ok {
+				return id.Name
+			}
+			return ""
+		}
+
+		spec, ok := obj.Decl.(*ast.TypeSpec)
+		if obj.Kind != ast.Typ || !ok {
+			return ""
+		}
+
+		typ = spec.Type
+	}
+
+	return ""
+}
+
+// compositeLit returns the composite literal, parenthesized or which address is taken, e.g. &Config{...}
+func compositeLit(expr ast.Expr) (*ast.CompositeLit, bool) {
+	expr = ast.Unparen(expr)
+
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		expr = ast.Unparen(u.X)
+	}
+
+	lit, ok := expr.(*ast.CompositeLit)
+
+	return lit, ok
+}
+
+// isTypeName reports whether the expression is a predeclared basic type or a type declared in the file;
+// types of other files and packages can't be told from functions without type information
+func (f *file) isTypeName(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return f.isTypeName(e.X)
+	case *ast.Ident:
+		if e.Obj != nil {
+			return e.Obj.Kind == ast.Typ
+		}
+
+		if f.ast.Scope != nil {
+			if obj := f.ast.Scope.Lookup(e.Name); obj != nil {
+				return obj.Kind == ast.Typ
+			}
+		}
+
+		_, ok := basicTypes[e.Name]
+		return ok
+	}
+
+	return false
+}
+
+// basicTypes contains names of predeclared basic types
+var basicTypes = map[string]struct{}{
+	"bool": {}, "string": {}, "byte": {}, "rune": {}, "uintptr": {},
+	"int": {}, "int8": {}, "int16": {}, "int32": {}, "int64": {},
+	"uint": {}, "uint8": {}, "uint16": {}, "uint32": {}, "uint64": {},
+	"float32": {}, "float64": {}, "complex64": {}, "complex128": {},
+}
+
+// initExpr returns the initializer of a package-level variable or constant of the file the identifier refers to
+func (f *file) initExpr(id *ast.Ident) ast.Expr {
+	if f.ast.Scope == nil {
+		return nil
+	}
+
+	obj := f.ast.Scope.Lookup(id.Name)
+	if obj == nil || (id.Obj != nil && id.Obj != obj) {
+		// unknown or shadowed by a local declaration
+		return nil
+	}
+
+	if obj.Kind != ast.Var && obj.Kind != ast.Con {
+		return nil
+	}
+
+	spec, ok := obj.Decl.(*ast.ValueSpec)
+	if !ok {
+		return nil
+	}
+
+	return f.specValue(spec, obj.Name)
+}
+
+// specValue returns the value of the name declared by the spec,
+// taking into account implicit repetition of expressions in const blocks
+func (f *file) specValue(spec *ast.ValueSpec, name string) ast.Expr {
+	values := spec.Values
+
+	if len(values) == 0 {
+		decl := f.genDecl(spec)
+		if decl == nil || decl.Tok != token.CONST {
+			return nil
+		}
+
+		for _, s := range decl.Specs {
+			vs := s.(*ast.ValueSpec)
+			if len(vs.Values) > 0 {
+				values = vs.Values
+			}
+			if vs == spec {
+				break
+			}
+		}
+	}
+
+	for i, n := range spec.Names {
+		if n.Name == name && i < len(values) {
+			return values[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package goparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnconvert(t *testing.T) {
+	p := newTestParser(t, `package p
+
+type MyString string
+
+type Port int
+
+// p
+var a = float32(3)
+
+// p
+var b = float64(2.5)
+
+// p
+var c = MyString("x")
+
+// p
+var d = Port(80)
+
+// p
+var e = int(3)
+
+// p
+var f = (float64)(-1)
+`)
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "int", got: basicValues(GetBasicValues[int](p, "p")), want: map[string]int{"d": 80, "e": 3}},
+		{name: "float64", got: basicValues(GetBasicValues[float64](p, "p")), want: map[string]float64{"a": 3, "b": 2.5, "f": -1}},
+		{name: "string", got: basicValues(GetBasicValues[string](p, "p")), want: map[string]string{"c": "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
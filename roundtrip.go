@@ -0,0 +1,198 @@
+package goparser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"reflect"
+)
+
+// Mismatch contains a value that didn't survive the round trip
+type Mismatch struct {
+	Name     string
+	Expected any
+	Actual   any
+	Reason   string
+}
+
+// CheckRoundTrip generates a defaults file from the results (see GenerateDefaults),
+// parses it, extracts the values by their labels again and reports values that aren't equal to the original ones
+// or declared with other types;
+// WithFormatter and WithHeader options are respected
+func CheckRoundTrip(results []Result, opts ...Option) ([]Mismatch, error) {
+	f := NewGenFile("roundtrip", opts...)
+	if err := GenerateDefaults(f, results); err != nil {
+		return nil, err
+	}
+
+	src, err := f.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := newFromSource("roundtrip.go", src, newOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("generated file doesn't parse: %w", err)
+	}
+
+	docs := make(map[string]struct{})
+	for _, r := range results {
+		docs[r.Doc] = struct{}{}
+	}
+
+	extracted := make(map[string]ast.Expr)
+	extractedTypes := make(map[string]string)
+	var genFile *file
+	appendDeclsFunc[struct{}](nil, g, docs, func(dst []struct{}, f *file, info DeclInfo, val ast.Expr) []struct{} {
+		extracted[info.Name] = val
+		extractedTypes[info.Name] = info.Type
+		genFile = f
+		return dst
+	})
+
+	mismatches := make([]Mismatch, 0)
+
+	for _, r := range results {
+		val, ok := extracted[r.Name]
+		if !ok {
+			mismatches = append(mismatches, Mismatch{Name: r.Name, Expected: r.Value, Reason: "not extracted"})
+			continue
+		}
+
+		if typ := extractedTypes[r.Name]; typ != r.Type {
+			mismatches = append(mismatches, Mismatch{Name: r.Name, Expected: r.Type, Actual: typ, Reason: "types differ"})
+			continue
+		}
+
+		if r.Kind == KindAlloc || r.Kind == KindSelectorRef {
+			// values are sources of the expressions, they're compared as formatted by go/types
+			if actual, expected := types.ExprString(val), exprSource(r.Value); actual != expected {
+				mismatches = append(mismatches, Mismatch{Name: r.Name, Expected: r.Value, Actual: actual, Reason: "values differ"})
+			}
+			continue
+		}
+
+		actual, err := genFile.reparse(val, r.Value)
+		if err != nil {
+			mismatches = append(mismatches, Mismatch{Name: r.Name, Expected: r.Value, Reason: err.Error()})
+			continue
+		}
+
+		if !reflect.DeepEqual(actual, r.Value) {
+			mismatches = append(mismatches, Mismatch{Name: r.Name, Expected: r.Value, Actual: actual, Reason: "values differ"})
+		}
+	}
+
+	return mismatches, nil
+}
+
+// exprSource returns the source of the expression formatted by go/types, e.g. "make([]int, 0, 8)"
+// of "make([]int,0,8)"; sources that don't parse are returned as is
+func exprSource(v any) string {
+	src := fmt.Sprint(v)
+
+	expr, err := parser.ParseExpr(src)
+	if err != nil {
+		return src
+	}
+
+	return types.ExprString(expr)
+}
+
+// reparse parses the expression of the file into a value of the same type and shape as the original one
+func (f *file) reparse(val ast.Expr, original any) (any, error) {
+	if _, ok := original.(string); ok {
+		if sel, ok := val.(*ast.SelectorExpr); ok {
+			ref, ok := f.selectorRef(sel)
+			if !ok {
+				return nil, fmt.Errorf("can't parse selector")
+			}
+			return string(ref), nil
+		}
+	}
+
+	if isEmptyComposite(original) {
+		if lit, ok := val.(*ast.CompositeLit); !ok || len(lit.Elts) > 0 {
+			return nil, fmt.Errorf("not empty")
+		}
+		return original, nil
+	}
+
+	t := reflect.TypeOf(original)
+
+	entries, isMap := original.([]Entry)
+	if isMap {
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("empty map")
+		}
+		t = reflect.MapOf(reflect.TypeOf(entries[0].Key), reflect.TypeOf(entries[0].Value))
+	}
+
+	maps, isSliceMap := original.([][]Entry)
+	if isSliceMap {
+		if len(maps) == 0 || len(maps[0]) == 0 {
+			return nil, fmt.Errorf("empty map")
+		}
+		t = reflect.SliceOf(reflect.MapOf(reflect.TypeOf(maps[0][0].Key), reflect.TypeOf(maps[0][0].Value)))
+	}
+
+	if t == nil {
+		return nil, fmt.Errorf("nil value")
+	}
+
+	v, ok := parseValue(val, t, nil)
+	if !ok {
+		return nil, fmt.Errorf("can't parse %s", t)
+	}
+
+	if isMap {
+		return setEntryFormats(mapEntries(v), val), nil
+	}
+
+	if isSliceMap {
+		lit, _ := compositeLit(val)
+		result := make([][]Entry, v.Len())
+		for i := range result {
+			result[i] = setEntryFormats(mapEntries(v.Index(i)), lit.Elts[i])
+		}
+		return result, nil
+	}
+
+	return v.Interface(), nil
+}
+
+// setEntryFormats sets formats of the entries which values are written as integer literals in the map literal
+func setEntryFormats(entries []Entry, val ast.Expr) []Entry {
+	lit, ok := compositeLit(val)
+	if !ok || len(entries) == 0 {
+		return entries
+	}
+
+	keyType := reflect.TypeOf(entries[0].Key)
+
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		format, ok := litFormat(kv.Value)
+		if !ok {
+			continue
+		}
+
+		k, ok := parseValue(kv.Key, keyType, nil)
+		if !ok {
+			continue
+		}
+
+		for i := range entries {
+			if entries[i].Key == k.Interface() {
+				entries[i].Format = &format
+			}
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,49 @@
+package goparser
+
+import (
+	"testing"
+)
+
+func TestCheckRoundTrip(t *testing.T) {
+	p := newTestParser(t, `package p
+
+import "time"
+
+// cfg
+var port = 8080
+
+// cfg
+var hosts = []string{"a", "b"}
+
+// cfg
+var cache = make(map[string]int,   8)
+
+// cfg
+var counter = new(int)
+
+// cfg
+var timeout = time.Second
+`)
+
+	results := CollectValues(p, "cfg").Results
+	if len(results) != 5 {
+		t.Fatalf("got %d results, want 5: %+v", len(results), results)
+	}
+
+	kinds := make(map[string]int)
+	for _, r := range results {
+		kinds[r.Kind]++
+	}
+	if kinds[KindAlloc] != 2 || kinds[KindSelectorRef] != 1 {
+		t.Fatalf("got result kinds %v", kinds)
+	}
+
+	mismatches, err := CheckRoundTrip(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mismatches) > 0 {
+		t.Errorf("got mismatches %+v", mismatches)
+	}
+}
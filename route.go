@@ -0,0 +1,133 @@
+package goparser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Route maps results with a label to an output file; the format is chosen by the file extension:
+// .json (an indented report, see WriteJSON), .jsonl (JSON lines), .yaml or .yml (see WriteYAML)
+// and .pb (a protobuf report, see MarshalProto)
+type Route struct {
+	Label string
+	Path  string
+}
+
+// ParseRoute parses a route written as "label=path", e.g. "parser:db=db_defaults.json"
+func ParseRoute(s string) (Route, error) {
+	label, path, ok := strings.Cut(s, "=")
+	if !ok || label == "" || path == "" {
+		return Route{}, fmt.Errorf("invalid route %q, expected label=path", s)
+	}
+
+	if _, err := routeFormat(path); err != nil {
+		return Route{}, err
+	}
+
+	return Route{Label: label, Path: path}, nil
+}
+
+// Filter returns a report of results matched by any of the labels, labels may be glob patterns, e.g. "parser:*"
+func (r *Report) Filter(labels ...string) *Report {
+	f := NewReport()
+	f.SchemaVersion = r.SchemaVersion
+
+	labelMap := make(map[string]struct{}, len(labels))
+	for _, l := range labels {
+		labelMap[l] = struct{}{}
+	}
+	m := newLabelMatcher(labelMap, nil)
+
+	for _, res := range r.Results {
+		if m.match(res.Doc) || slices.ContainsFunc(res.Labels, m.match) {
+			f.Results = append(f.Results, res)
+		}
+	}
+
+	return f
+}
+
+// WriteRoutes writes results of the report to the files of the routes, so results of several labels
+// collected in a single scan are written to several artifacts; results of routes with the same path
+// are written to the same file:
+//
+//	report := CollectValues(p, "parser:db", "parser:http")
+//	err := report.WriteRoutes([]Route{
+//	    {Label: "parser:db", Path: "db_defaults.json"},
+//	    {Label: "parser:http", Path: "http_defaults.yaml"},
+//	})
+func (r *Report) WriteRoutes(routes []Route) error {
+	paths := make([]string, 0, len(routes))
+	labels := make(map[string][]string, len(routes))
+
+	for _, rt := range routes {
+		if _, ok := labels[rt.Path]; !ok {
+			paths = append(paths, rt.Path)
+		}
+		labels[rt.Path] = append(labels[rt.Path], rt.Label)
+	}
+
+	for _, path := range paths {
+		data, err := r.Filter(labels[path]...).encode(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if err = os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// route formats
+const (
+	routeJSON  = "json"
+	routeJSONL = "jsonl"
+	routeYAML  = "yaml"
+	routeProto = "proto"
+)
+
+// routeFormat returns the format of the output file by its extension
+func routeFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return routeJSON, nil
+	case ".jsonl":
+		return routeJSONL, nil
+	case ".yaml", ".yml":
+		return routeYAML, nil
+	case ".pb":
+		return routeProto, nil
+	}
+
+	return "", fmt.Errorf("unsupported output format of %q, expected .json, .jsonl, .yaml, .yml or .pb", path)
+}
+
+// encode returns the report in the format of the output file
+func (r *Report) encode(path string) ([]byte, error) {
+	format, err := routeFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	switch format {
+	case routeJSON:
+		err = r.WriteJSON(&buf)
+	case routeJSONL:
+		err = r.Stream(NewWriterSink(&buf))
+	case routeYAML:
+		err = r.WriteYAML(&buf)
+	case routeProto:
+		return r.MarshalProto()
+	}
+
+	return buf.Bytes(), err
+}
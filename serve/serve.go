@@ -0,0 +1,187 @@
+// Package serve provides extraction of labeled values over HTTP, e.g. to centralize parsing in a build farm
+//
+//	http.Handle("/", &serve.Server{Root: "/src"})
+//
+// Requests:
+//
+//	POST /extract?label=config&label=limits&kind=basic
+//
+// The request body is either Go source or a JSON object with the source or a path relative to the server root:
+//
+//	{"name": "config.go", "source": "package config ..."}
+//	{"path": "internal/config"}
+//
+// The response is a goparser report (see goparser.Report) containing values of common types (see goparser.CollectValues),
+// optionally filtered by result kinds; errors are returned as {"error": "..."} with a 4xx status code
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gp "github.com/goiste/goparser"
+)
+
+// DefaultMaxBodySize is the default limit of request bodies
+const DefaultMaxBodySize = 10 << 20
+
+// Server handles extraction requests
+type Server struct {
+	// Root is the directory request paths are resolved in; requests with paths are rejected if it's empty
+	Root string
+
+	// Options are used for every parser
+	Options []gp.Option
+
+	// MaxBodySize limits request bodies, DefaultMaxBodySize is used if it's zero
+	MaxBodySize int64
+}
+
+// request is a JSON extraction request
+type request struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Path   string `json:"path"`
+}
+
+// ServeHTTP handles POST /extract requests
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/extract" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown path %q", r.URL.Path))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	labels := queryList(r, "label")
+	if len(labels) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("no labels"))
+		return
+	}
+
+	p, status, err := s.parse(w, r)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	report := gp.CollectValues(p, labels...)
+
+	if kinds := queryList(r, "kind"); len(kinds) > 0 {
+		report.Results = filterKinds(report.Results, kinds)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = report.WriteJSON(w)
+}
+
+// parse returns a parser of the request source or path
+func (s *Server) parse(w http.ResponseWriter, r *http.Request) (*gp.GoParser, int, error) {
+	maxSize := s.MaxBodySize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBodySize
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSize))
+	if err != nil {
+		return nil, http.StatusRequestEntityTooLarge, err
+	}
+
+	req := request{Name: r.URL.Query().Get("name"), Source: string(body)}
+
+	if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct == "application/json" {
+		req = request{}
+		if err = json.Unmarshal(body, &req); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+
+	if req.Path != "" {
+		return s.parsePath(req.Path)
+	}
+
+	if req.Name == "" {
+		req.Name = "source.go"
+	}
+
+	p, err := gp.NewFromSource(req.Name, []byte(req.Source), s.Options...)
+	if err != nil {
+		return nil, http.StatusUnprocessableEntity, err
+	}
+
+	return p, http.StatusOK, nil
+}
+
+// parsePath returns a parser of the file or the directory relative to the server root
+func (s *Server) parsePath(path string) (*gp.GoParser, int, error) {
+	if s.Root == "" {
+		return nil, http.StatusForbidden, errors.New("paths are not allowed")
+	}
+
+	full := filepath.Join(s.Root, filepath.FromSlash(path))
+
+	rel, err := filepath.Rel(s.Root, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, http.StatusForbidden, fmt.Errorf("path %q is outside of the root", path)
+	}
+
+	stat, err := os.Stat(full)
+	if err != nil {
+		return nil, http.StatusNotFound, fmt.Errorf("path %q not found", path)
+	}
+
+	var p *gp.GoParser
+	if stat.IsDir() {
+		p, err = gp.NewFromDir(full, s.Options...)
+	} else {
+		p, err = gp.New(full, s.Options...)
+	}
+	if err != nil {
+		return nil, http.StatusUnprocessableEntity, err
+	}
+
+	return p, http.StatusOK, nil
+}
+
+// queryList returns values of the query parameter, repeated or comma-separated
+func queryList(r *http.Request, name string) []string {
+	var list []string
+	for _, v := range r.URL.Query()[name] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+func filterKinds(results []gp.Result, kinds []string) []gp.Result {
+	filtered := make([]gp.Result, 0, len(results))
+	for _, res := range results {
+		for _, k := range kinds {
+			if res.Kind == k {
+				filtered = append(filtered, res)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
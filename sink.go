@@ -0,0 +1,122 @@
+package goparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// Sink receives exported results one by one, e.g. to stream them to a service without buffering a whole report
+type Sink interface {
+	Write(r Result) error
+}
+
+// SinkFunc is a function implementing Sink
+type SinkFunc func(r Result) error
+
+// Write calls the function
+func (f SinkFunc) Write(r Result) error {
+	return f(r)
+}
+
+// WriteResults writes the values to the sink, stopping at the first error
+//
+//	err := WriteResults(NewStdoutSink(), GetBasicValues[string](p, "someLabel"))
+func WriteResults[T Exportable](s Sink, values []T) error {
+	for _, v := range values {
+		if err := s.Write(v.Result()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stream writes the results of the report to the sink, stopping at the first error
+func (r *Report) Stream(s Sink) error {
+	for _, res := range r.Results {
+		if err := s.Write(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writerSink writes results as JSON lines
+type writerSink struct {
+	enc *json.Encoder
+}
+
+// NewWriterSink returns a sink writing results to w as JSON lines
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{enc: json.NewEncoder(w)}
+}
+
+// NewStdoutSink returns a sink writing results to the standard output as JSON lines
+func NewStdoutSink() Sink {
+	return NewWriterSink(os.Stdout)
+}
+
+func (s *writerSink) Write(r Result) error {
+	return s.enc.Encode(r)
+}
+
+// FileSink writes results to a file as JSON lines, it must be closed after writing
+type FileSink struct {
+	f *os.File
+	Sink
+}
+
+// NewFileSink creates or truncates the file and returns a sink writing results to it as JSON lines
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSink{f: f, Sink: NewWriterSink(f)}, nil
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	return s.f.Close()
+}
+
+// httpSink posts results as JSON
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink returns a sink posting each result as JSON to the URL, responses with non-2xx status codes are errors;
+// http.DefaultClient is used if the client is nil
+func NewHTTPSink(url string, client *http.Client) Sink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &httpSink{url: url, client: client}
+}
+
+func (s *httpSink) Write(r Result) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("posting %s to %s: %s", r.Name, s.url, resp.Status)
+	}
+
+	return nil
+}
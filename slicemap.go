@@ -0,0 +1,139 @@
+package goparser
+
+import (
+	"go/ast"
+	"reflect"
+)
+
+// SliceMapLitValue contains slice of maps with literal keys and values
+type SliceMapLitValue[K, V iLit] struct {
+	DeclInfo
+	Value []map[K]V
+
+	// Formats describes how integer literal values of the maps are written by their keys, see LitValue.Format
+	Formats []map[K]NumFormat
+}
+
+// Result returns the value as Result
+func (v SliceMapLitValue[K, V]) Result() Result {
+	entries := make([][]Entry, len(v.Value))
+	for i, m := range v.Value {
+		entries[i] = mapEntries(reflect.ValueOf(m))
+		if i < len(v.Formats) {
+			entries[i] = withEntryFormats(entries[i], v.Formats[i])
+		}
+	}
+
+	return newResult(KindSliceMap, v.DeclInfo, entries)
+}
+
+// GetSliceMapValues returns a list of values containing slices of maps with literal types as keys and values by godoc label,
+// e.g. routing tables or test matrices
+//
+//	// someLabel
+//	var testVar = []map[string]string{{"path": "/"}, {"path": "/api"}}
+func GetSliceMapValues[K, V iLit](g *GoParser, docLabels ...string) []SliceMapLitValue[K, V] {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	t := reflect.TypeOf([]map[K]V(nil))
+
+	return walkDecls(g, docMap, func(info DeclInfo, val ast.Expr) *SliceMapLitValue[K, V] {
+		lim := g.opts.newLimiter()
+
+		v, ok := parseValue(val, t, lim)
+		if !ok || !v.IsValid() || (v.Len() == 0 && !lim.isTruncated()) {
+			return nil
+		}
+
+		info.Truncated = lim.isTruncated()
+
+		return &SliceMapLitValue[K, V]{
+			DeclInfo: info,
+			Value:    v.Interface().([]map[K]V),
+			Formats:  sliceMapFormats[K](val, v.Len()),
+		}
+	})
+}
+
+// sliceMapFormats returns formats of integer literal values of the first n maps of the slice literal by their keys
+func sliceMapFormats[K iLit](val ast.Expr, n int) []map[K]NumFormat {
+	lit, ok := compositeLit(val)
+	if !ok {
+		return nil
+	}
+
+	formats := make([]map[K]NumFormat, n)
+	for i := range formats {
+		formats[i] = make(map[K]NumFormat)
+
+		m, ok := compositeLit(lit.Elts[i])
+		if !ok {
+			continue
+		}
+
+		for _, elt := range m.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			format, ok := litFormat(kv.Value)
+			if !ok {
+				continue
+			}
+
+			if k, ok := parseElt[K](kv.Key); ok && k != nil {
+				formats[i][*k] = format
+			}
+		}
+	}
+
+	return formats
+}
+
+// MapSliceLitValue contains map with literal keys and slices of literals as values
+type MapSliceLitValue[K, V iLit] struct {
+	DeclInfo
+	Value map[K][]V
+}
+
+// GetMapSliceValues returns a list of values containing maps with literal types as keys and slices of literal types
+// as values by godoc label, e.g. lists of hosts by environment
+//
+//	// someLabel
+//	var testVar = map[string][]string{"hosts": {"a", "b"}}
+func GetMapSliceValues[K, V iLit](g *GoParser, docLabels ...string) []MapSliceLitValue[K, V] {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	t := reflect.TypeOf(map[K][]V(nil))
+
+	return walkDecls(g, docMap, func(info DeclInfo, val ast.Expr) *MapSliceLitValue[K, V] {
+		lim := g.opts.newLimiter()
+
+		v, ok := parseValue(val, t, lim)
+		if !ok || !v.IsValid() || (v.Len() == 0 && !lim.isTruncated()) {
+			return nil
+		}
+
+		info.Truncated = lim.isTruncated()
+
+		return &MapSliceLitValue[K, V]{
+			DeclInfo: info,
+			Value:    v.Interface().(map[K][]V),
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// StructValue contains a struct literal as a map of field names to values
+type StructValue struct {
+	DeclInfo
+
+	// StructType is the type of the literal written as in the source, e.g. "Config"
+	StructType string
+
+	// Value contains values of keyed fields: basic values typed as by InferValues, time.Duration for expressions
+	// of time units (`30 * time.Second`), map[string]any for struct literals, []any for slices and arrays
+	// and map[any]any for maps; fields of unkeyed literals and values that aren't literals are omitted
+	Value map[string]any
+}
+
+// GetStructValues returns a list of struct literals by godoc label as maps of field names to values,
+// nested composite literals and referenced variables of the file are extracted recursively
+//
+//	// parser:defaults
+//	var Defaults = Config{Host: "x", Port: 80, DB: DBConfig{Name: "app"}}
+//	// map[string]any{"Host": "x", "Port": int64(80), "DB": map[string]any{"Name": "app"}}
+func GetStructValues(g *GoParser, docLabels ...string) []StructValue {
+	if g.noLabels(docLabels) {
+		return nil
+	}
+
+	docMap := make(map[string]struct{}, len(docLabels))
+	for _, doc := range docLabels {
+		docMap[doc] = struct{}{}
+	}
+
+	return appendDeclsFunc(newResults[StructValue](g), g, docMap, func(dst []StructValue, f *file, info DeclInfo, val ast.Expr) []StructValue {
+		lit, ok := compositeLit(val)
+		if !ok || lit.Type == nil || !isStructLit(lit) {
+			return dst
+		}
+
+		lim := g.opts.newLimiter()
+
+		v, ok := g.compositeValue(f, val, nil, g.opts.resolveDepth, lim)
+		m, isStruct := v.(map[string]any)
+		if !ok || !isStruct {
+			return dst
+		}
+
+		info.Truncated = lim.isTruncated()
+
+		return append(dst, StructValue{DeclInfo: info, StructType: types.ExprString(lit.Type), Value: m})
+	})
+}
+
+// compositeValue returns the value of an expression: a basic value inferred from the literal (see inferValue)
+// or a value of a composite literal (see StructValue); typ is the type of a literal with an elided type,
+// depth limits following references to variables of the file, so that cyclic references terminate
+func (g *GoParser) compositeValue(f *file, val, typ ast.Expr, depth int, lim *limiter) (any, bool) {
+	if _, ok := ast.Unparen(val).(*ast.Ident); ok {
+		if depth <= 0 {
+			return nil, false
+		}
+		depth--
+	}
+
+	val = f.resolve(val, depth)
+
+	lit, ok := compositeLit(val)
+	if !ok {
+		if v, ok := f.inferValue(floatLit(typ, g.constLit(f, nil, val, -1))); ok {
+			return v, true
+		}
+		return f.durationValue(val, depth)
+	}
+
+	if lit.Type != nil {
+		typ = lit.Type
+	} else if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+
+	if !lim.enter() {
+		return nil, false
+	}
+	defer lim.leave()
+
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		s := make([]any, 0, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			if !lim.add() {
+				break
+			}
+
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+
+			if v, ok := g.compositeValue(f, elt, t.Elt, depth, lim); ok {
+				s = append(s, v)
+			}
+		}
+
+		return s, true
+	case *ast.MapType:
+		m := make(map[any]any, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			if !lim.add() {
+				break
+			}
+
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			// keys are basic values only, values of composite literals aren't comparable
+			k, ok := f.inferValue(floatLit(t.Key, g.constLit(f, nil, f.resolve(kv.Key, depth), -1)))
+			if !ok {
+				continue
+			}
+
+			if v, ok := g.compositeValue(f, kv.Value, t.Value, depth, lim); ok {
+				m[k] = v
+			}
+		}
+
+		return m, true
+	}
+
+	if !isStructLit(lit) {
+		return nil, false
+	}
+
+	m := make(map[string]any, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		if !lim.add() {
+			break
+		}
+
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		if v, ok := g.compositeValue(f, kv.Value, nil, depth, lim); ok {
+			m[key.Name] = v
+		}
+	}
+
+	return m, true
+}
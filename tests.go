@@ -0,0 +1,230 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Test function kinds
+const (
+	TestKindTest      = "Test"
+	TestKindBenchmark = "Benchmark"
+	TestKindFuzz      = "Fuzz"
+	TestKindExample   = "Example"
+)
+
+// Subtest contains a subtest started by t.Run (or b.Run) with a string literal name;
+// names of nested subtests are joined with "/"
+type Subtest struct {
+	Name string
+	Pos  token.Position
+}
+
+// TestFunc contains a test, benchmark, fuzz test or example function
+type TestFunc struct {
+	Kind     string
+	Name     string
+	Pos      token.Position
+	Subtests []Subtest
+}
+
+// GetTests returns Test, Benchmark, Fuzz and Example functions with their subtests
+func GetTests(g *GoParser) []TestFunc {
+	result := make([]TestFunc, 0)
+
+	for _, f := range g.files {
+		for _, d := range f.ast.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Recv != nil {
+				continue
+			}
+
+			kind := testKind(decl.Name.Name)
+			if kind == "" {
+				continue
+			}
+
+			tf := TestFunc{
+				Kind:     kind,
+				Name:     decl.Name.Name,
+				Pos:      f.fset.Position(decl.Pos()),
+				Subtests: make([]Subtest, 0),
+			}
+
+			if decl.Body != nil {
+				tf.Subtests = findSubtests(f.fset, decl.Body, "", tf.Subtests)
+			}
+
+			result = append(result, tf)
+		}
+	}
+
+	return result
+}
+
+// testKind returns the kind of a test function by its name or an empty string
+func testKind(name string) string {
+	for _, kind := range []string{TestKindTest, TestKindBenchmark, TestKindFuzz, TestKindExample} {
+		if !strings.HasPrefix(name, kind) {
+			continue
+		}
+
+		// like the go tool: TestXxx, but not Testxxx
+		rest := name[len(kind):]
+		if rest == "" || kind == TestKindExample && rest[0] == '_' {
+			return kind
+		}
+
+		r, _ := utf8.DecodeRuneInString(rest)
+		if !unicode.IsLower(r) {
+			return kind
+		}
+	}
+
+	return ""
+}
+
+func findSubtests(fset *token.FileSet, body ast.Node, prefix string, result []Subtest) []Subtest {
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Run" {
+			return true
+		}
+
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+
+		if prefix != "" {
+			name = prefix + "/" + name
+		}
+
+		result = append(result, Subtest{Name: name, Pos: fset.Position(call.Pos())})
+
+		if fn, ok := call.Args[1].(*ast.FuncLit); ok {
+			result = findSubtests(fset, fn.Body, name, result)
+		}
+
+		return false
+	})
+
+	return result
+}
+
+// ExampleReport contains results of the example pairing check
+type ExampleReport struct {
+	// Missing contains exported functions, types and methods (as "Type.Method") without examples
+	Missing []string
+
+	// Orphans contains example functions referencing non-existent identifiers
+	Orphans []string
+}
+
+// CheckExamples reports exported identifiers lacking Example functions and examples referencing non-existent identifiers;
+// examples are paired by the go doc naming convention: ExampleF, ExampleT, ExampleT_M with an optional _suffix;
+// test functions and declarations of _test.go files aren't expected to have examples
+func CheckExamples(g *GoParser) ExampleReport {
+	exported := make(map[string]struct{})
+	order := make([]string, 0)
+	add := func(name string) {
+		if _, ok := exported[name]; !ok {
+			exported[name] = struct{}{}
+			order = append(order, name)
+		}
+	}
+
+	examples := make(map[string]string) // identifier: example function name
+	result := ExampleReport{Missing: make([]string, 0), Orphans: make([]string, 0)}
+
+	for _, f := range g.files {
+		testFile := strings.HasSuffix(f.path, "_test.go")
+
+		for _, d := range f.ast.Decls {
+			switch decl := d.(type) {
+			case *ast.FuncDecl:
+				kind := ""
+				if decl.Recv == nil {
+					kind = testKind(decl.Name.Name)
+				}
+
+				if kind == TestKindExample {
+					if id := exampleIdent(decl.Name.Name); id != "" {
+						if _, ok := examples[id]; !ok {
+							examples[id] = decl.Name.Name
+						}
+					}
+					continue
+				}
+
+				if kind != "" || testFile {
+					continue
+				}
+
+				rec := recvTypeName(decl)
+				if !decl.Name.IsExported() || (rec != "" && !ast.IsExported(rec)) {
+					continue
+				}
+
+				add(funcName(decl))
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE || testFile {
+					continue
+				}
+
+				for _, spec := range decl.Specs {
+					if ts := spec.(*ast.TypeSpec); ts.Name.IsExported() {
+						add(ts.Name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range order {
+		if _, ok := examples[name]; !ok {
+			result.Missing = append(result.Missing, name)
+		}
+	}
+
+	for _, name := range sortedKeys(examples) {
+		if _, ok := exported[name]; !ok {
+			result.Orphans = append(result.Orphans, examples[name])
+		}
+	}
+
+	return result
+}
+
+// exampleIdent returns the identifier referenced by an example function name ("F" or "T.M"),
+// an empty string for package examples
+func exampleIdent(name string) string {
+	rest := strings.TrimPrefix(name, TestKindExample)
+	if rest == "" || rest[0] == '_' {
+		return ""
+	}
+
+	parts := strings.Split(rest, "_")
+	if len(parts) > 1 {
+		r, _ := utf8.DecodeRuneInString(parts[len(parts)-1])
+		if unicode.IsLower(r) {
+			parts = parts[:len(parts)-1]
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
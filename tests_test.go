@@ -0,0 +1,63 @@
+package goparser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckExamples(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"p.go": `package p
+
+func Parse() {}
+
+func Format() {}
+
+type Config struct{}
+
+func (Config) Load() {}
+`,
+		"p_test.go": `package p
+
+import "testing"
+
+func TestParse(t *testing.T) {}
+
+func BenchmarkParse(b *testing.B) {}
+
+func FuzzParse(f *testing.F) {}
+
+func ExampleParse() {}
+
+func ExampleConfig_Load() {}
+
+func ExampleMissing() {}
+
+type Helper struct{}
+
+func NewHelper() *Helper { return nil }
+`,
+	}
+
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := NewFromDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := CheckExamples(p)
+	want := ExampleReport{Missing: []string{"Format", "Config"}, Orphans: []string{"ExampleMissing"}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,262 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// typeInfo contains type information of typed mode
+type typeInfo struct {
+	info *types.Info
+	pkgs []*types.Package
+	errs []error
+}
+
+// checkTypes type-checks parsed files grouped by directory and package name;
+// imported packages are type-checked from source, type errors are collected but don't fail the check
+func (g *GoParser) checkTypes() {
+	ti := &typeInfo{
+		info: &types.Info{
+			Types: make(map[ast.Expr]types.TypeAndValue),
+			Defs:  make(map[*ast.Ident]types.Object),
+			Uses:  make(map[*ast.Ident]types.Object),
+		},
+	}
+
+	type pkgKey struct {
+		dir  string
+		name string
+	}
+
+	groups := make(map[pkgKey][]*ast.File)
+	fsets := make(map[pkgKey]*token.FileSet)
+	keys := make([]pkgKey, 0)
+
+	for _, f := range g.files {
+		k := pkgKey{dir: filepath.Dir(f.path), name: f.ast.Name.Name}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+			fsets[k] = f.fset
+		}
+		groups[k] = append(groups[k], f.ast)
+	}
+
+	for _, k := range keys {
+		conf := types.Config{
+			Importer: importer.ForCompiler(fsets[k], "source", nil),
+			Error: func(err error) {
+				ti.errs = append(ti.errs, err)
+			},
+		}
+
+		pkg, _ := conf.Check(k.name, fsets[k], groups[k], ti.info)
+		ti.pkgs = append(ti.pkgs, pkg)
+	}
+
+	g.types = ti
+}
+
+// TypeErrors returns errors found by type-checking in typed mode
+func TypeErrors(g *GoParser) []error {
+	if g.types == nil {
+		return nil
+	}
+	return g.types.errs
+}
+
+// lookupType returns a type declared in parsed files by name
+func (g *GoParser) lookupType(name string) *types.TypeName {
+	if g.types == nil {
+		return nil
+	}
+
+	for _, pkg := range g.types.pkgs {
+		if pkg == nil {
+			continue
+		}
+
+		if tn, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok {
+			return tn
+		}
+	}
+
+	return nil
+}
+
+// constLit returns a basic literal (or a bool identifier) of the constant value of the expression the name
+// is initialized with: in typed mode it's taken from type information, e.g. of a constant imported from another package,
+// otherwise the expression is evaluated (see evalConst, iota is -1 outside of const declarations);
+// other expressions and literals are returned as is; elements of composite literals are replaced the same way (see constElts)
+func (g *GoParser) constLit(f *file, name *ast.Ident, val ast.Expr, iota int) ast.Expr {
+	if _, ok := basicLit(val); ok {
+		return val // keeps the format of integer literals
+	}
+
+	if lit, ok := val.(*ast.CompositeLit); ok {
+		return g.constElts(f, lit, lit.Type)
+	}
+
+	if call, ok := bytesCall(val); ok {
+		// []byte(secret) with const secret = "..." is []byte("...")
+		res := *call
+		res.Args = []ast.Expr{g.constLit(f, nil, call.Args[0], -1)}
+		return &res
+	}
+
+	var v constant.Value
+
+	if g.types != nil {
+		if c, ok := g.types.info.Defs[name].(*types.Const); ok {
+			v = c.Val()
+		} else if tv, ok := g.types.info.Types[val]; ok {
+			v = tv.Value
+		}
+	}
+
+	if v == nil {
+		if ev, ok := f.evalConst(val, g.opts.resolveDepth, iota); ok {
+			v = ev
+		}
+	}
+
+	if v == nil {
+		return val
+	}
+
+	lit := &ast.BasicLit{ValuePos: val.Pos()}
+
+	switch v.Kind() {
+	case constant.Bool:
+		return &ast.Ident{NamePos: val.Pos(), Name: strconv.FormatBool(constant.BoolVal(v))}
+	case constant.String:
+		lit.Kind, lit.Value = token.STRING, strconv.Quote(constant.StringVal(v))
+	case constant.Int:
+		lit.Kind, lit.Value = token.INT, v.ExactString()
+	case constant.Float:
+		fv, _ := constant.Float64Val(v)
+		lit.Kind, lit.Value = token.FLOAT, strconv.FormatFloat(fv, 'g', -1, 64)
+	default:
+		return val
+	}
+
+	return lit
+}
+
+// floatLit returns an integer literal as a floating-point one if the type is float32 or float64,
+// e.g. 3 of `var f float64 = 3` or 2 of `[]float64{1.5, 2}`; integers written in other bases (0x10, 0o17)
+// keep their values, other values are returned as is
+func floatLit(typ, val ast.Expr) ast.Expr {
+	id, ok := typ.(*ast.Ident)
+	if !ok || id.Obj != nil || (id.Name != "float32" && id.Name != "float64") {
+		return val
+	}
+
+	lit, ok := basicLit(val)
+	if !ok || lit.Kind != token.INT {
+		return val
+	}
+
+	sign, digits := "", lit.Value
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	v := constant.MakeFromLiteral(digits, token.INT, 0)
+	if v.Kind() != constant.Int {
+		return val
+	}
+
+	return &ast.BasicLit{ValuePos: lit.ValuePos, Kind: token.FLOAT, Value: sign + v.ExactString()}
+}
+
+// constElts returns a copy of the composite literal with constant elements replaced by literals (see constLit),
+// so references to constants and variables of the file are extracted as their values:
+//
+//	var a = map[string]int{"max": MaxSize} // map[string]int{"max": 1024} with const MaxSize = 1024
+//	var b = []string{defaultHost}          // []string{"localhost"} with var defaultHost = "localhost"
+//
+// typ is the type of the literal, it's passed to nested literals with elided types; keys are replaced
+// for maps, slices and arrays (indices) only, keys of struct literals are field names;
+// the length of an array type is replaced as well, e.g. `[size]int{...}` with const size = 3 is `[3]int{...}`
+func (g *GoParser) constElts(f *file, lit *ast.CompositeLit, typ ast.Expr) *ast.CompositeLit {
+	if lit.Type != nil {
+		typ = lit.Type
+	} else if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X // &T is elided as well, e.g. []*Point{{1, 2}}
+	}
+
+	res := *lit
+
+	var keyType, eltType ast.Expr
+	replaceKeys := false
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		eltType, replaceKeys = t.Elt, true
+
+		if _, ok := t.Len.(*ast.Ellipsis); ok || t.Len == nil || t != lit.Type {
+			break
+		}
+
+		arr := *t
+		arr.Len = g.constLit(f, nil, t.Len, -1)
+		res.Type = &arr
+	case *ast.MapType:
+		keyType, eltType, replaceKeys = t.Key, t.Value, true
+	}
+
+	elt := func(val, typ ast.Expr) ast.Expr {
+		if c, ok := compositeLit(val); ok {
+			return g.constElts(f, c, typ)
+		}
+		return floatLit(typ, g.constLit(f, nil, val, -1))
+	}
+
+	res.Elts = make([]ast.Expr, len(lit.Elts))
+
+	for i, e := range lit.Elts {
+		kv, ok := e.(*ast.KeyValueExpr)
+		if !ok {
+			res.Elts[i] = elt(e, eltType)
+			continue
+		}
+
+		key := kv.Key
+		if replaceKeys {
+			key = elt(key, keyType)
+		}
+
+		res.Elts[i] = &ast.KeyValueExpr{Key: key, Colon: kv.Colon, Value: elt(kv.Value, eltType)}
+	}
+
+	return &res
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// isErrorType reports whether the type expression is error or, in typed mode, a type implementing it
+func (g *GoParser) isErrorType(t ast.Expr) bool {
+	if id, ok := t.(*ast.Ident); ok && id.Name == "error" {
+		return true
+	}
+
+	if g.types == nil {
+		return false
+	}
+
+	typ := g.types.info.TypeOf(t)
+	return typ != nil && types.Implements(typ, errorType)
+}
+
+// exprString returns the source of a type expression, empty for nil
+func exprString(t ast.Expr) string {
+	if t == nil {
+		return ""
+	}
+	return types.ExprString(t)
+}
@@ -0,0 +1,232 @@
+package goparser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"os"
+	"slices"
+	"strings"
+)
+
+// GenManifestPrefix starts the trailing comment of generated files containing the compact JSON manifest
+// of source declarations the file was generated from, see CheckUpToDate
+const GenManifestPrefix = "//goparser:manifest "
+
+// GenManifest describes source declarations a generated file was produced from
+type GenManifest struct {
+	Sources []GenSource `json:"sources"`
+}
+
+// GenSource contains a source declaration of a generated file
+type GenSource struct {
+	Name     string   `json:"name"`
+	Func     string   `json:"func,omitempty"`
+	Package  string   `json:"package,omitempty"`
+	Labels   []string `json:"labels"`
+	Checksum string   `json:"checksum"`
+}
+
+// key identifies the declaration in a set of parsed packages
+func (s GenSource) key() string {
+	return s.Package + "\x00" + s.Func + "\x00" + s.Name
+}
+
+// Stale reasons
+const (
+	StaleChanged = "changed" // the value, the name or the doc comment of the declaration has changed
+	StaleRemoved = "removed" // the declaration or its label is removed
+	StaleAdded   = "added"   // a new declaration is labeled with one of the labels of the manifest
+)
+
+// StaleSource contains a source declaration that differs from the one a generated file was produced from
+type StaleSource struct {
+	Name string
+	Func string
+
+	// Reason is one of StaleChanged, StaleRemoved and StaleAdded
+	Reason string
+}
+
+// addSources records the results in the manifest of the file, declarations recorded before are skipped
+func (f *GenFile) addSources(results []Result) {
+	for _, r := range results {
+		if slices.ContainsFunc(f.sources, func(s GenSource) bool {
+			return s.Name == r.Name && s.Func == r.Func && s.Package == r.Package
+		}) {
+			continue
+		}
+
+		labels := r.Labels
+		if len(labels) == 0 && r.Doc != "" {
+			labels = []string{r.Doc}
+		}
+
+		f.sources = append(f.sources, GenSource{
+			Name:     r.Name,
+			Func:     r.Func,
+			Package:  r.Package,
+			Labels:   labels,
+			Checksum: r.Checksum,
+		})
+	}
+}
+
+// manifest returns the trailing manifest comment of the file, it's empty if no sources were recorded
+func (f *GenFile) manifest() (string, error) {
+	if len(f.sources) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(GenManifest{Sources: f.sources})
+	if err != nil {
+		return "", err
+	}
+
+	return "\n" + GenManifestPrefix + string(data) + "\n", nil
+}
+
+// ReadGenManifest returns the manifest embedded in the source of a generated file, it's nil if there's none
+func ReadGenManifest(src []byte) (*GenManifest, error) {
+	var data string
+
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	sc.Buffer(nil, len(src)+1)
+	for sc.Scan() {
+		if line := sc.Text(); strings.HasPrefix(line, GenManifestPrefix) {
+			data = strings.TrimPrefix(line, GenManifestPrefix)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if data == "" {
+		return nil, nil
+	}
+
+	var m GenManifest
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	return &m, nil
+}
+
+// CheckUpToDate reads the manifest embedded in the generated file (by GenerateDefaults or GenerateRegistry)
+// and compares its source declarations with ones extracted from the parsers by the labels of the manifest;
+// declarations labeled with them that aren't in the manifest are reported as added,
+// so the file is expected to be generated from all values of its labels; an empty result means the file is up to date:
+//
+//	stale, err := goparser.CheckUpToDate("defaults_gen.go", p)
+//	if len(stale) > 0 {
+//	    // run go generate
+//	}
+func CheckUpToDate(genFile string, parsers ...*GoParser) ([]StaleSource, error) {
+	src, err := os.ReadFile(genFile)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := ReadGenManifest(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", genFile, err)
+	}
+	if m == nil {
+		return nil, fmt.Errorf("%s: no manifest", genFile)
+	}
+
+	return checkUpToDate(genFile, m, parsers), nil
+}
+
+// checkUpToDate returns source declarations of the manifest of the generated file differing from parsed ones
+func checkUpToDate(genFile string, m *GenManifest, parsers []*GoParser) []StaleSource {
+
+	docMap := make(map[string]struct{})
+	sources := make(map[string]GenSource, len(m.Sources))
+	for _, s := range m.Sources {
+		for _, l := range s.Labels {
+			docMap[l] = struct{}{}
+		}
+		sources[s.key()] = s
+	}
+
+	result := make([]StaleSource, 0)
+	found := make(map[string]struct{}, len(sources))
+
+	genPath := overlayKey(genFile)
+
+	for _, g := range parsers {
+		walkDecls(g, docMap, func(info DeclInfo, _ ast.Expr) *struct{} {
+			if overlayKey(info.Pos.Filename) == genPath {
+				return nil // labeled declarations of the generated file itself
+			}
+
+			s := GenSource{Name: info.Name, Func: info.Func, Package: info.Package}
+			key := s.key()
+
+			if _, ok := found[key]; ok {
+				return nil
+			}
+			found[key] = struct{}{}
+
+			old, ok := sources[key]
+			switch {
+			case !ok:
+				result = append(result, StaleSource{Name: info.Name, Func: info.Func, Reason: StaleAdded})
+			case old.Checksum != info.Checksum:
+				result = append(result, StaleSource{Name: info.Name, Func: info.Func, Reason: StaleChanged})
+			}
+
+			return nil
+		})
+	}
+
+	for _, s := range m.Sources {
+		if _, ok := found[s.key()]; !ok {
+			found[s.key()] = struct{}{}
+			result = append(result, StaleSource{Name: s.Name, Func: s.Func, Reason: StaleRemoved})
+		}
+	}
+
+	return result
+}
+
+// Stale contains a generated file that is out of date or can't be checked
+type Stale struct {
+	File    string
+	Sources []StaleSource
+	Err     error
+}
+
+// VerifyGenerated parses the directory and its subdirectories (see NewFromDir) and checks all generated files
+// with an embedded manifest against the parsed declarations (see CheckUpToDate), e.g. as a CI check
+// that go generate was run; the directory is expected to contain the sources of the generated files.
+// Out of date files are returned, a single entry with Err is returned if the directory can't be parsed
+func VerifyGenerated(dir string, opts ...Option) []Stale {
+	g, err := NewFromDir(dir, opts...)
+	if err != nil {
+		return []Stale{{File: dir, Err: err}}
+	}
+
+	result := make([]Stale, 0)
+
+	for _, f := range g.files {
+		m, err := ReadGenManifest(f.src)
+		if err != nil {
+			result = append(result, Stale{File: f.path, Err: err})
+			continue
+		}
+		if m == nil {
+			continue
+		}
+
+		if sources := checkUpToDate(f.path, m, []*GoParser{g}); len(sources) > 0 {
+			result = append(result, Stale{File: f.path, Sources: sources})
+		}
+	}
+
+	return result
+}
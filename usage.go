@@ -0,0 +1,148 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// usageIndex contains positions of references to package-level names of a package,
+// references in Example functions come first
+type usageIndex map[string][]token.Position
+
+// firstUsage returns the position of the first reference to the declared name in its package
+// other than the declaration itself, references in Example functions are preferred
+func (g *GoParser) firstUsage(f *file, name *ast.Ident) token.Position {
+	if g.usages == nil {
+		g.usages = make(map[string]usageIndex)
+	}
+
+	key := pkgKey(f)
+
+	idx, ok := g.usages[key]
+	if !ok {
+		idx = g.indexUsages(key)
+		g.usages[key] = idx
+	}
+
+	declPos := f.fset.Position(name.Pos())
+	for _, pos := range idx[name.Name] {
+		if pos != declPos {
+			return pos
+		}
+	}
+
+	return token.Position{}
+}
+
+// indexUsages collects references to package-level names in the files of the package (including its external tests)
+func (g *GoParser) indexUsages(key string) usageIndex {
+	type usage struct {
+		pos     token.Position
+		example bool
+	}
+
+	usages := make(map[string][]usage)
+
+	// names declared in the package scope, unresolved identifiers of a file refer to them
+	// if they are declared in other files of the package
+	names := make(map[string]struct{})
+	for _, f := range g.files {
+		if pkgKey(f) == key && f.ast.Scope != nil && !strings.HasSuffix(f.ast.Name.Name, "_test") {
+			for name := range f.ast.Scope.Objects {
+				names[name] = struct{}{}
+			}
+		}
+	}
+
+	for _, f := range g.files {
+		if pkgKey(f) != key {
+			continue
+		}
+
+		pkgName := strings.TrimSuffix(f.ast.Name.Name, "_test")
+		external := pkgName != f.ast.Name.Name
+
+		for _, d := range f.ast.Decls {
+			example := false
+			if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil {
+				example = strings.HasPrefix(fn.Name.Name, "Example")
+			}
+
+			add := func(id *ast.Ident) {
+				usages[id.Name] = append(usages[id.Name], usage{pos: f.fset.Position(id.Pos()), example: example})
+			}
+
+			var visit func(n ast.Node) bool
+			visit = func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.SelectorExpr:
+					// only package-qualified selectors refer to package-level names, fields and methods don't
+					if x, ok := n.X.(*ast.Ident); ok && external && x.Obj == nil && x.Name == pkgName {
+						add(n.Sel)
+						return false
+					}
+					ast.Inspect(n.X, visit)
+					return false
+				case *ast.KeyValueExpr:
+					// keys of struct literals are field names, they can't be told from keys of map literals
+					if _, ok := n.Key.(*ast.Ident); !ok {
+						ast.Inspect(n.Key, visit)
+					}
+					ast.Inspect(n.Value, visit)
+					return false
+				case *ast.FuncDecl:
+					// names of methods aren't references
+					if n.Recv != nil {
+						ast.Inspect(n.Recv, visit)
+						ast.Inspect(n.Type, visit)
+						if n.Body != nil {
+							ast.Inspect(n.Body, visit)
+						}
+						return false
+					}
+				case *ast.Ident:
+					if !external && isPackageLevel(f.ast, names, n) {
+						add(n)
+					}
+				}
+				return true
+			}
+
+			ast.Inspect(d, visit)
+		}
+	}
+
+	idx := make(usageIndex, len(usages))
+	for name, us := range usages {
+		sort.SliceStable(us, func(i, j int) bool {
+			return us[i].example && !us[j].example
+		})
+
+		positions := make([]token.Position, len(us))
+		for i, u := range us {
+			positions[i] = u.pos
+		}
+		idx[name] = positions
+	}
+
+	return idx
+}
+
+// isPackageLevel reports whether the identifier refers to a package-level name: it's either resolved
+// to a declaration of the file scope or unresolved and one of the names declared in other files of the package
+func isPackageLevel(f *ast.File, names map[string]struct{}, id *ast.Ident) bool {
+	if id.Obj == nil {
+		_, ok := names[id.Name]
+		return ok
+	}
+
+	return f.Scope != nil && f.Scope.Lookup(id.Name) == id.Obj
+}
+
+// pkgKey identifies the package of the file by its directory and package name (without the _test suffix)
+func pkgKey(f *file) string {
+	return filepath.Dir(f.path) + "\x00" + strings.TrimSuffix(f.ast.Name.Name, "_test")
+}
@@ -0,0 +1,56 @@
+package goparser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUsage(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.go": `package p
+
+// cfg
+var timeout = 5
+`,
+		"b.go": `package p
+
+type client struct{ timeout int }
+
+type server struct{}
+
+func (server) timeout() int { return 0 }
+
+func newClient() client { return client{timeout: 1} }
+
+func (c client) wait() int { return c.timeout + len("x") }
+
+func use() int {
+	return timeout
+}
+`,
+	}
+
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := NewFromDir(dir, WithUsages())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := GetBasicValues[int64](p, "cfg")
+	if len(values) != 1 {
+		t.Fatalf("got %d values, want 1", len(values))
+	}
+
+	usage := values[0].Usage
+	if filepath.Base(usage.Filename) != "b.go" || usage.Line != 14 {
+		t.Errorf("got usage %s, want b.go:14", usage)
+	}
+}
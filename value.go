@@ -0,0 +1,143 @@
+package goparser
+
+import (
+	"go/ast"
+	"reflect"
+)
+
+// parseValue parses a basic literal or a composite literal of a slice or a map of basic literals into a value of the type;
+// composite literals exceeding the limits are omitted (the value is invalid then), elements exceeding them are skipped
+func parseValue(expr ast.Expr, t reflect.Type, l *limiter) (reflect.Value, bool) {
+	switch t.Kind() {
+	case reflect.Slice:
+		cmp, ok := ast.Unparen(expr).(*ast.CompositeLit)
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		if !l.enter() {
+			return reflect.Value{}, true
+		}
+		defer l.leave()
+
+		s := reflect.MakeSlice(t, 0, len(cmp.Elts))
+		for _, elt := range cmp.Elts {
+			if !l.add() {
+				break
+			}
+
+			v, ok := parseValue(elt, t.Elem(), l)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			if v.IsValid() {
+				s = reflect.Append(s, v)
+			}
+		}
+
+		return s, true
+	case reflect.Map:
+		cmp, ok := ast.Unparen(expr).(*ast.CompositeLit)
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		if !l.enter() {
+			return reflect.Value{}, true
+		}
+		defer l.leave()
+
+		m := reflect.MakeMapWithSize(t, len(cmp.Elts))
+		for _, elt := range cmp.Elts {
+			if !l.add() {
+				break
+			}
+
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return reflect.Value{}, false
+			}
+
+			k, kOk := parseValue(kv.Key, t.Key(), l)
+			v, vOk := parseValue(kv.Value, t.Elem(), l)
+			if !kOk || !vOk {
+				return reflect.Value{}, false
+			}
+			if v.IsValid() {
+				m.SetMapIndex(k, v)
+			}
+		}
+
+		return m, true
+	}
+
+	v, ok := parseBasic(expr, t.Kind())
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	return reflect.ValueOf(v).Convert(t), true
+}
+
+// parseBasic parses a basic literal, concatenated string literals or a bool constant into a value of the kind
+func parseBasic(expr ast.Expr, kind reflect.Kind) (any, bool) {
+	if kind == reflect.Bool {
+		id, ok := ast.Unparen(expr).(*ast.Ident)
+		if !ok {
+			return nil, false
+		}
+		return boolIdent(id)
+	}
+
+	if kind == reflect.String {
+		if lit, ok := foldStrings(expr); ok {
+			expr = lit
+		}
+	}
+
+	lit, ok := basicLit(expr)
+	if !ok {
+		return nil, false
+	}
+
+	switch kind {
+	case reflect.String:
+		return basicAs[string](lit)
+	case reflect.Int:
+		return basicAs[int](lit)
+	case reflect.Int8:
+		return basicAs[int8](lit)
+	case reflect.Int16:
+		return basicAs[int16](lit)
+	case reflect.Int32:
+		return basicAs[int32](lit)
+	case reflect.Int64:
+		return basicAs[int64](lit)
+	case reflect.Uint:
+		return basicAs[uint](lit)
+	case reflect.Uintptr:
+		return basicAs[uintptr](lit)
+	case reflect.Uint8:
+		return basicAs[uint8](lit)
+	case reflect.Uint16:
+		return basicAs[uint16](lit)
+	case reflect.Uint32:
+		return basicAs[uint32](lit)
+	case reflect.Uint64:
+		return basicAs[uint64](lit)
+	case reflect.Float32:
+		return basicAs[float32](lit)
+	case reflect.Float64:
+		return basicAs[float64](lit)
+	}
+
+	return nil, false
+}
+
+func basicAs[V iLit](lit *ast.BasicLit) (any, bool) {
+	v := parseBasicLit[V](lit)
+	if v == nil {
+		return nil, false
+	}
+	return *v, true
+}
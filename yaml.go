@@ -0,0 +1,189 @@
+package goparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// WriteYAML writes the report as YAML with the same structure and field names as its JSON form (see WriteJSON)
+func (r *Report) WriteYAML(w io.Writer) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	node, err := readJSONNode(dec)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err = writeYAML(&buf, node, 0); err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf.Bytes())
+
+	return err
+}
+
+// jsonField is a field of a JSON object, objects are read as []jsonField to keep the order of fields
+type jsonField struct {
+	key string
+	val any
+}
+
+// readJSONNode reads a JSON value: []jsonField for objects, []any for arrays, string, json.Number, bool or nil
+func readJSONNode(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := make([]jsonField, 0)
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			val, err := readJSONNode(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			obj = append(obj, jsonField{key: key.(string), val: val})
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := make([]any, 0)
+		for dec.More() {
+			val, err := readJSONNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, val)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+
+	return tok, nil
+}
+
+// writeYAML writes the node in block style, nested nodes are indented by two spaces
+func writeYAML(buf *bytes.Buffer, node any, indent int) error {
+	pad := strings.Repeat(" ", indent)
+
+	switch n := node.(type) {
+	case []jsonField:
+		for _, f := range n {
+			buf.WriteString(pad + yamlKey(f.key) + ":")
+			if err := writeYAMLValue(buf, f.val, indent+2); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, v := range n {
+			if obj, ok := v.([]jsonField); ok && len(obj) > 0 {
+				// the first field of a mapping follows the entry indicator
+				var sub bytes.Buffer
+				if err := writeYAML(&sub, obj, indent+2); err != nil {
+					return err
+				}
+				buf.WriteString(pad + "- " + strings.TrimPrefix(sub.String(), pad+"  "))
+				continue
+			}
+
+			buf.WriteString(pad + "-")
+			if err := writeYAMLValue(buf, v, indent+2); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeYAMLValue writes the value following a key or a sequence entry indicator
+func writeYAMLValue(buf *bytes.Buffer, val any, indent int) error {
+	switch v := val.(type) {
+	case []jsonField:
+		if len(v) == 0 {
+			buf.WriteString(" {}\n")
+			return nil
+		}
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return nil
+		}
+	default:
+		s, err := yamlScalar(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(" " + s + "\n")
+		return nil
+	}
+
+	buf.WriteString("\n")
+
+	return writeYAML(buf, val, indent)
+}
+
+// yamlScalar returns a scalar node, strings are double-quoted (a JSON string is a valid YAML double-quoted scalar)
+func yamlScalar(v any) (string, error) {
+	switch s := v.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		if s {
+			return "true", nil
+		}
+		return "false", nil
+	case json.Number:
+		return s.String(), nil
+	}
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+var yamlPlainKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// yamlKey returns the key as a plain scalar if possible, a quoted one otherwise
+func yamlKey(key string) string {
+	if yamlPlainKey.MatchString(key) && !isYAMLKeyword(key) {
+		return key
+	}
+
+	s, _ := yamlScalar(key)
+
+	return s
+}
+
+// isYAMLKeyword reports whether a plain scalar would be read as a bool or null
+func isYAMLKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "null", "yes", "no", "on", "off", "y", "n":
+		return true
+	}
+	return false
+}